@@ -0,0 +1,253 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dirNameRuns          = "runs"
+	dirNameWorkflows     = "workflows"
+	dirNameRunLogs       = "logs"
+	dirNameRunArtifacts  = "artifacts"
+	dirNameLatestSuccess = "latest-success"
+	fileNameRunInfo      = "run.json"
+)
+
+// WithActions includes the actions directory with the workflow runs and
+// workflows of each repo.  Reading them takes several REST API calls per repo,
+// so they are only included when asked for.
+func WithActions() Option {
+	return func(gfs *FS) {
+		gfs.actions = true
+	}
+}
+
+// restRun is a workflow run as returned by the REST API.
+type restRun struct {
+	Id         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	Event      string    `json:"event"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	HeadBranch string    `json:"head_branch"`
+	HeadSha    string    `json:"head_sha"`
+	RunNumber  int       `json:"run_number"`
+	RunAttempt int       `json:"run_attempt"`
+	Url        string    `json:"html_url"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// restWorkflow is a workflow as returned by the REST API.
+type restWorkflow struct {
+	Id        int64     `json:"id"`
+	Path      string    `json:"path"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// restJob is a job of a workflow run as returned by the REST API.
+type restJob struct {
+	Id          int64     `json:"id"`
+	Name        string    `json:"name"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// restArtifact is an artifact of a workflow run as returned by the REST API.
+type restArtifact struct {
+	Name        string    `json:"name"`
+	Expired     bool      `json:"expired"`
+	DownloadUrl string    `json:"archive_download_url"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// runInfo is the metadata about a workflow run written to run.json.
+type runInfo struct {
+	Id         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Workflow   string    `json:"workflow"`
+	Event      string    `json:"event"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	Branch     string    `json:"branch"`
+	Commit     string    `json:"commit"`
+	RunNumber  int       `json:"runNumber"`
+	RunAttempt int       `json:"runAttempt"`
+	Url        string    `json:"url"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// actionsUrl returns the REST API url for the actions of the repo.
+func actionsUrl(gfs *FS, d *dir, parts ...string) string {
+	return strings.Join(append([]string{gfs.restUrl, "repos", d.org, d.repo, "actions"}, parts...), "/")
+}
+
+// newActions creates the actions directory with the runs and workflows.
+func newActions(r *dir) {
+	a := r.mkdir(dirNameActions, notInPath())
+	a.mkdir(dirNameRuns, withFetcher(getRunsDir), withLookup(lookupRun), notInPath())
+	a.mkdir(dirNameWorkflows, withFetcher(getWorkflowsDir), notInPath())
+}
+
+// getRunsDir fetches the most recent page of workflow runs.  Older runs are
+// found by lookupRun when they are opened by id.  A repo with actions disabled,
+// or that the token can't read the actions of, has no runs.
+func getRunsDir(gfs *FS, d *dir) error {
+	var resp struct {
+		Runs []restRun `json:"workflow_runs"`
+	}
+	if err := restGet(context.Background(), gfs, actionsUrl(gfs, d, "runs")+"?per_page=100", &resp); err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			return nil
+		}
+		return err
+	}
+
+	for _, run := range resp.Runs {
+		if err := newRun(d, strconv.FormatInt(run.Id, 10), run); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lookupRun fetches a workflow run by id and adds it to the runs directory.
+func lookupRun(gfs *FS, d *dir, name string) error {
+	if _, err := strconv.ParseInt(name, 10, 64); err != nil {
+		return fmt.Errorf("run %s is not an id %w", name, fs.ErrNotExist)
+	}
+
+	var run restRun
+	if err := restGet(context.Background(), gfs, actionsUrl(gfs, d, "runs", name), &run); err != nil {
+		return err
+	}
+
+	return newRun(d, name, run)
+}
+
+// getWorkflowsDir fetches the workflows and makes a directory for each named
+// after the workflow file.  The workflows of a repo the token can't read are
+// left out.
+func getWorkflowsDir(gfs *FS, d *dir) error {
+	err := restPages(gfs, actionsUrl(gfs, d, "workflows"), "workflows",
+		func(workflows []restWorkflow) error {
+			for _, w := range workflows {
+				d.newDir(path.Base(w.Path),
+					withDirModTime(w.UpdatedAt),
+					withFetcher(latestSuccessFetcher(w.Id)),
+					notInPath())
+			}
+			return nil
+		})
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+		return nil
+	}
+	return err
+}
+
+// latestSuccessFetcher returns the fetcher for the directory of a workflow
+// that adds the latest successful run of the workflow as latest-success.  A
+// workflow the token can't read the runs of has no latest successful run.
+func latestSuccessFetcher(id int64) func(*FS, *dir) error {
+	return func(gfs *FS, d *dir) error {
+		var resp struct {
+			Runs []restRun `json:"workflow_runs"`
+		}
+		u := actionsUrl(gfs, d, "workflows", strconv.FormatInt(id, 10), "runs") + "?status=success&per_page=1"
+		if err := restGet(context.Background(), gfs, u, &resp); err != nil {
+			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+				return nil
+			}
+			return err
+		}
+
+		if len(resp.Runs) == 0 {
+			return nil
+		}
+
+		return newRun(d, dirNameLatestSuccess, resp.Runs[0])
+	}
+}
+
+// newRun makes the directory for a workflow run with the run metadata, and
+// the logs and artifacts directories that are fetched when used.
+func newRun(d *dir, name string, run restRun) error {
+	info := runInfo{
+		Id:         run.Id,
+		Name:       run.Name,
+		Workflow:   run.Path,
+		Event:      run.Event,
+		Status:     run.Status,
+		Conclusion: run.Conclusion,
+		Branch:     run.HeadBranch,
+		Commit:     run.HeadSha,
+		RunNumber:  run.RunNumber,
+		RunAttempt: run.RunAttempt,
+		Url:        run.Url,
+		CreatedAt:  run.CreatedAt,
+		UpdatedAt:  run.UpdatedAt,
+	}
+
+	buf, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	id := strconv.FormatInt(run.Id, 10)
+	runDir := d.newDir(name, withDirModTime(run.UpdatedAt), notInPath())
+	runDir.addFile(fileNameRunInfo, withContent(buf), withModTime(run.UpdatedAt))
+	runDir.newDir(dirNameRunLogs, withDirModTime(run.UpdatedAt), withFetcher(logsFetcher(id)), notInPath())
+	runDir.newDir(dirNameRunArtifacts, withDirModTime(run.UpdatedAt), withFetcher(artifactsFetcher(id)), notInPath())
+
+	return nil
+}
+
+// logsFetcher returns the fetcher for the logs directory of a run that adds
+// the log of each job as <job name>.txt.
+func logsFetcher(id string) func(*FS, *dir) error {
+	return func(gfs *FS, d *dir) error {
+		return restPages(gfs, actionsUrl(gfs, d, "runs", id, "jobs"), "jobs",
+			func(jobs []restJob) error {
+				for _, job := range jobs {
+					d.addFile(strings.ReplaceAll(job.Name, "/", "_")+".txt",
+						withUrl(actionsUrl(gfs, d, "jobs", strconv.FormatInt(job.Id, 10), "logs")),
+						withUnknownSize(),
+						withModTime(job.CompletedAt))
+				}
+				return nil
+			})
+	}
+}
+
+// artifactsFetcher returns the fetcher for the artifacts directory of a run
+// that adds each artifact that hasn't expired as <name>.zip.
+func artifactsFetcher(id string) func(*FS, *dir) error {
+	return func(gfs *FS, d *dir) error {
+		return restPages(gfs, actionsUrl(gfs, d, "runs", id, "artifacts"), "artifacts",
+			func(artifacts []restArtifact) error {
+				for _, artifact := range artifacts {
+					if artifact.Expired {
+						continue
+					}
+					d.addFile(artifact.Name+".zip",
+						withUrl(artifact.DownloadUrl),
+						withUnknownSize(),
+						withModTime(artifact.UpdatedAt))
+				}
+				return nil
+			})
+	}
+}
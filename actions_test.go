@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"testing"
+)
+
+func TestActions(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "fetch the recent runs",
+			opts:        []Option{WithRepo("org", "repo"), WithActions()},
+			payload:     []string{singleRepoReponse, runsResponse},
+			contents: map[string]string{
+				"org/repo/actions/runs/30/run.json": run30Json,
+			},
+			requests: []string{
+				"POST /",
+				"GET /repos/org/repo/actions/runs?per_page=100",
+			},
+		}, {
+			description: "fetch an older run by id",
+			opts:        []Option{WithRepo("org", "repo"), WithActions()},
+			payload:     []string{singleRepoReponse, runsResponse, run7Response},
+			expect:      []string{"org/repo/actions/runs/7/run.json"},
+			unexpected:  []string{"org/repo/actions/runs/latest"},
+			requests: []string{
+				"POST /",
+				"GET /repos/org/repo/actions/runs?per_page=100",
+				"GET /repos/org/repo/actions/runs/7",
+			},
+		}, {
+			description: "a run that doesn't exist",
+			opts:        []Option{WithRepo("org", "repo"), WithActions()},
+			statusCode:  []int{0, 0, 404},
+			payload:     []string{singleRepoReponse, runsResponse, `{"message":"Not Found"}`},
+			unexpected:  []string{"org/repo/actions/runs/8"},
+		}, {
+			description: "fetch the logs and artifacts of a run",
+			opts:        []Option{WithRepo("org", "repo"), WithActions()},
+			payload: []string{singleRepoReponse, runsResponse,
+				artifactsResponse, artifactZip,
+				jobsResponse, jobLog},
+			unexpected: []string{"org/repo/actions/runs/30/artifacts/old.zip"},
+			contents: map[string]string{
+				"org/repo/actions/runs/30/artifacts/dist.zip":  artifactZip,
+				"org/repo/actions/runs/30/logs/build_test.txt": jobLog,
+			},
+			requests: []string{
+				"POST /",
+				"GET /repos/org/repo/actions/runs?per_page=100",
+				"GET /repos/org/repo/actions/runs/30/artifacts?per_page=100&page=1",
+				"GET /repos/org/repo/actions/artifacts/5/zip",
+				"GET /repos/org/repo/actions/runs/30/jobs?per_page=100&page=1",
+				"GET /repos/org/repo/actions/jobs/300/logs",
+			},
+		}, {
+			description: "fetch the latest successful run of a workflow",
+			opts:        []Option{WithRepo("org", "repo"), WithActions()},
+			payload:     []string{singleRepoReponse, workflowsResponse, runsResponse},
+			contents: map[string]string{
+				"org/repo/actions/workflows/ci.yml/latest-success/run.json": run30Json,
+			},
+			requests: []string{
+				"POST /",
+				"GET /repos/org/repo/actions/workflows?per_page=100&page=1",
+				"GET /repos/org/repo/actions/workflows/2/runs?status=success&per_page=1",
+			},
+		}, {
+			description: "a workflow without a successful run",
+			opts:        []Option{WithRepo("org", "repo"), WithActions()},
+			payload:     []string{singleRepoReponse, workflowsResponse, noRunsResponse},
+			expect:      []string{"org/repo/actions/workflows/ci.yml"},
+			unexpected:  []string{"org/repo/actions/workflows/ci.yml/latest-success"},
+		}, {
+			description: "a workflow the token can't read the runs of",
+			opts:        []Option{WithRepo("org", "repo"), WithActions()},
+			statusCode:  []int{0, 0, 403},
+			payload:     []string{singleRepoReponse, workflowsResponse, "{}"},
+			expect:      []string{"org/repo/actions/workflows/ci.yml"},
+			unexpected:  []string{"org/repo/actions/workflows/ci.yml/latest-success"},
+		}, {
+			description: "a workflow whose runs can't be found",
+			opts:        []Option{WithRepo("org", "repo"), WithActions()},
+			statusCode:  []int{0, 0, 404},
+			payload:     []string{singleRepoReponse, workflowsResponse, "{}"},
+			expect:      []string{"org/repo/actions/workflows/ci.yml"},
+			unexpected:  []string{"org/repo/actions/workflows/ci.yml/latest-success"},
+		}, {
+			description: "fetch the runs, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo"), WithActions()},
+			payload:     []string{singleRepoReponse, invalidJsonResponse},
+			expect:      []string{"org/repo/actions/runs/30"},
+			expectErr:   true,
+		}, {
+			description: "runs the token can't read are empty",
+			opts:        []Option{WithRepo("org", "repo"), WithActions()},
+			statusCode:  []int{0, 403},
+			payload:     []string{singleRepoReponse, "{}"},
+			expect:      []string{"org/repo/actions/runs"},
+			unexpected:  []string{"org/repo/actions/runs/30"},
+		}, {
+			description: "workflows of a repo with actions disabled are empty",
+			opts:        []Option{WithRepo("org", "repo"), WithActions()},
+			statusCode:  []int{0, 404},
+			payload:     []string{singleRepoReponse, "{}"},
+			expect:      []string{"org/repo/actions/workflows"},
+			unexpected:  []string{"org/repo/actions/workflows/ci.yml"},
+		}, {
+			description: "actions aren't included unless asked for",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse},
+			unexpected:  []string{"org/repo/actions"},
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+var runsResponse = `{
+  "total_count": 1,
+  "workflow_runs": [
+    {
+      "id": 30,
+      "name": "CI",
+      "path": ".github/workflows/ci.yml",
+      "event": "push",
+      "status": "completed",
+      "conclusion": "success",
+      "head_branch": "main",
+      "head_sha": "1111111111111111111111111111111111111111",
+      "run_number": 12,
+      "run_attempt": 1,
+      "html_url": "https://github.com/org/repo/actions/runs/30",
+      "created_at": "2023-05-01T10:00:00Z",
+      "updated_at": "2023-05-01T10:05:00Z"
+    }
+  ]
+}`
+
+var noRunsResponse = `{
+  "total_count": 0,
+  "workflow_runs": []
+}`
+
+var run7Response = `{
+  "id": 7,
+  "name": "CI",
+  "path": ".github/workflows/ci.yml",
+  "event": "pull_request",
+  "status": "completed",
+  "conclusion": "failure",
+  "head_branch": "feature",
+  "head_sha": "2222222222222222222222222222222222222222",
+  "run_number": 3,
+  "run_attempt": 2,
+  "html_url": "https://github.com/org/repo/actions/runs/7",
+  "created_at": "2023-04-01T10:00:00Z",
+  "updated_at": "2023-04-01T10:05:00Z"
+}`
+
+var run30Json = `{
+  "id": 30,
+  "name": "CI",
+  "workflow": ".github/workflows/ci.yml",
+  "event": "push",
+  "status": "completed",
+  "conclusion": "success",
+  "branch": "main",
+  "commit": "1111111111111111111111111111111111111111",
+  "runNumber": 12,
+  "runAttempt": 1,
+  "url": "https://github.com/org/repo/actions/runs/30",
+  "createdAt": "2023-05-01T10:00:00Z",
+  "updatedAt": "2023-05-01T10:05:00Z"
+}`
+
+var workflowsResponse = `{
+  "total_count": 1,
+  "workflows": [
+    {
+      "id": 2,
+      "path": ".github/workflows/ci.yml",
+      "updated_at": "2023-01-01T10:00:00Z"
+    }
+  ]
+}`
+
+var jobsResponse = `{
+  "total_count": 1,
+  "jobs": [
+    {
+      "id": 300,
+      "name": "build/test",
+      "completed_at": "2023-05-01T10:04:00Z"
+    }
+  ]
+}`
+
+var jobLog = "2023-05-01T10:01:00.0000000Z ok  github.com/org/repo\n"
+
+var artifactsResponse = `{
+  "total_count": 2,
+  "artifacts": [
+    {
+      "name": "dist",
+      "expired": false,
+      "archive_download_url": "OVERWRITEURL/repos/org/repo/actions/artifacts/5/zip",
+      "updated_at": "2023-05-01T10:05:00Z"
+    },
+    {
+      "name": "old",
+      "expired": true,
+      "archive_download_url": "OVERWRITEURL/repos/org/repo/actions/artifacts/4/zip",
+      "updated_at": "2023-05-01T10:05:00Z"
+    }
+  ]
+}`
+
+var artifactZip = "PK\x05\x06\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+)
+
+// gitTimestamp is the github GitTimestamp scalar used in queries.
+type gitTimestamp string
+
+// GetGraphQLType returns the name of the github type.
+func (gitTimestamp) GetGraphQLType() string {
+	return "GitTimestamp"
+}
+
+// WithAsOf reads each configured branch as it was at the specified time
+// instead of the head of the branch.  The last commit at or before the time
+// is used.  Branches with no commits before the time are not included.
+//
+// Wikis aren't read as of the time since only the head of the wiki is
+// fetched.  Use WithLock() to pin a wiki to a commit.
+//
+// A single branch can also be read at a point in time by appending the time
+// to the branch directory name like this:
+//
+// org/repo/git/main@2026-03-01T00:00:00Z/...
+func WithAsOf(t time.Time) Option {
+	return func(gfs *FS) {
+		gfs.asOf = t
+	}
+}
+
+// splitAsOf splits a name like "main@2026-03-01T00:00:00Z" or
+// "main@2026-03-01" into the branch and the point in time.
+func splitAsOf(name string) (string, time.Time, bool) {
+	i := strings.LastIndex(name, "@")
+	if i < 1 {
+		return "", time.Time{}, false
+	}
+
+	branch, when := name[:i], name[i+1:]
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, when); err == nil {
+			return branch, t, true
+		}
+	}
+
+	return "", time.Time{}, false
+}
+
+// lookupAsOf resolves a "branch@time" name in the git directory, or one of the
+// directories of a branch name like "release/1.0", to the last commit on the
+// branch at that time and adds a directory pinned to the commit.
+func lookupAsOf(gfs *FS, d *dir, name string) error {
+	base, t, ok := splitAsOf(name)
+	if !ok {
+		return nil
+	}
+
+	// Include the directories between the git directory and this directory
+	// in the branch name.
+	prefix := strings.TrimPrefix(d.fullPath(), d.org+"/"+d.repo+"/"+dirNameGit)
+	branch := strings.TrimPrefix(prefix+"/"+base, "/")
+
+	oid, err := resolveAsOf(gfs, d.org, d.repo, branch, t)
+	if err != nil {
+		return err
+	}
+
+	d.newDir(name, withBranch(branch), withCommit(oid), notInPath(), gfs.treeFetcher(d.size))
+	return nil
+}
+
+// resolveAsOf asks github for the last commit on the branch at or before the
+// specified time.
+func resolveAsOf(gfs *FS, org, repo, branch string, t time.Time) (string, error) {
+	vars := map[string]any{
+		"owner":  org,
+		"repo":   repo,
+		"branch": refPrefixBranches + branch,
+		"until":  gitTimestamp(t.UTC().Format(time.RFC3339)),
+	}
+
+	/*
+		query {
+		  repository(name: "repo", owner: "org") {
+		    ref(qualifiedName: "refs/heads/main") {
+		      target {
+		        ... on Commit {
+		          history(first: 1, until: "2026-03-01T00:00:00Z") {
+		            nodes {
+		              oid
+		            }
+		          }
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	var query struct {
+		Repository struct {
+			Ref struct {
+				Target struct {
+					Commit struct {
+						History struct {
+							Nodes []struct {
+								Oid string
+							}
+						} `graphql:"history(first: 1, until: $until)"`
+					} `graphql:"... on Commit"`
+				}
+			} `graphql:"ref(qualifiedName: $branch)"`
+		} `graphql:"repository(name: $repo, owner: $owner)"`
+	}
+
+	if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+		return "", err
+	}
+
+	nodes := query.Repository.Ref.Target.Commit.History.Nodes
+	if len(nodes) == 0 || len(nodes[0].Oid) == 0 {
+		return "", fmt.Errorf("branch %s has no commits before %s %w",
+			branch, t.Format(time.RFC3339), fs.ErrNotExist)
+	}
+
+	return nodes[0].Oid, nil
+}
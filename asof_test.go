@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitAsOf(t *testing.T) {
+	tests := []struct {
+		name         string
+		expectBranch string
+		expectTime   time.Time
+		expectOk     bool
+	}{
+		{
+			name:         "main@2026-03-01T00:00:00Z",
+			expectBranch: "main",
+			expectTime:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			expectOk:     true,
+		}, {
+			name:         "main@2026-03-01",
+			expectBranch: "main",
+			expectTime:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			expectOk:     true,
+		}, {
+			name:         "user@host@2026-03-01T12:30:00+02:00",
+			expectBranch: "user@host",
+			expectTime:   time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC),
+			expectOk:     true,
+		}, {
+			name: "main",
+		}, {
+			name: "main@yesterday",
+		}, {
+			name: "@2026-03-01",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			branch, when, ok := splitAsOf(tc.name)
+			assert.Equal(tc.expectOk, ok)
+			assert.Equal(tc.expectBranch, branch)
+			assert.True(tc.expectTime.Equal(when))
+		})
+	}
+}
+
+func TestAsOf(t *testing.T) {
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []fsTest{
+		{
+			description: "read a branch at a point in time",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoReponse, historyResponse, baseDirectoryResponse, readmeResponse},
+			contents: map[string]string{
+				"org/repo/git/main@2026-03-01T00:00:00Z/README.md": readmeResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"POST /",
+				"GET /org/repo/8888888888888888888888888888888888888888/README.md",
+			},
+		}, {
+			description: "read a nested branch at a point in time",
+			opts:        []Option{WithRepo("org", "repo", "release/2.0"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoReponse, historyResponse, baseDirectoryResponse, baseDirectoryResponse},
+			expect:      []string{"org/repo/git/release/1.0@2026-03-01", "org/repo/git/release/2.0"},
+		}, {
+			description: "a branch with no commits at the point in time",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse, historyEmptyResponse},
+			unexpected:  []string{"org/repo/git/main@2001-03-01"},
+		}, {
+			description: "a branch name that is not a point in time",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse},
+			unexpected:  []string{"org/repo/git/main@yesterday"},
+			requests:    []string{"POST /"},
+		}, {
+			description: "read a branch at a point in time, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse, invalidJsonResponse},
+			expect:      []string{"org/repo/git/main@2026-03-01"},
+			expectErr:   true,
+		}, {
+			description: "read the configured branches at a point in time",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0), WithAsOf(march)},
+			payload:     []string{singleRepoWithHeadReponse, historyResponse, baseDirectoryResponse, readmeResponse},
+			contents: map[string]string{
+				"org/repo/git/main/README.md": readmeResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"POST /",
+				"GET /org/repo/8888888888888888888888888888888888888888/README.md",
+			},
+		}, {
+			description: "read the discovered branches at a point in time",
+			opts:        []Option{WithBranches("org", "repo", "release/*"), WithAsOf(march)},
+			payload:     []string{singleRepoWithHeadReponse, branchesWithHeadsResponse, historyEmptyResponse},
+			expect:      []string{"org/repo/git"},
+			unexpected:  []string{"org/repo/git/release/1.0", "org/repo/git/main"},
+		}, {
+			description: "the configured branch has no commits at the point in time",
+			opts:        []Option{WithRepo("org", "repo"), WithAsOf(march)},
+			payload:     []string{singleRepoWithHeadReponse, historyEmptyResponse},
+			expect:      []string{"org/repo/git"},
+			unexpected:  []string{"org/repo/git/main"},
+		}, {
+			description: "read the configured branches at a point in time, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo"), WithAsOf(march)},
+			payload:     []string{singleRepoWithHeadReponse, invalidJsonResponse},
+			expect:      []string{"org/repo/git/main"},
+			expectErr:   true,
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+var historyResponse = `{
+  "data": {
+    "repository": {
+      "ref": {
+        "target": {
+          "history": {
+            "nodes": [
+              {
+                "oid": "8888888888888888888888888888888888888888"
+              }
+            ]
+          }
+        }
+      }
+    }
+  }
+}`
+
+var historyEmptyResponse = `{
+  "data": {
+    "repository": {
+      "ref": {
+        "target": {
+          "history": {
+            "nodes": []
+          }
+        }
+      }
+    }
+  }
+}`
@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+const blameExt = ".blame.json"
+
+// WithBlameFiles makes the blame of each file in a git tree readable as a
+// <file>.blame.json file next to it.  The blame files aren't listed, they are
+// fetched when opened.
+func WithBlameFiles() Option {
+	return func(gfs *FS) {
+		gfs.blameFiles = true
+	}
+}
+
+// BlameRange is a range of lines of a file that were last changed by the same
+// commit.
+type BlameRange struct {
+	// StartLine is the first line of the range, starting at 1.
+	StartLine int `json:"startLine"`
+
+	// EndLine is the last line of the range.
+	EndLine int `json:"endLine"`
+
+	// Sha is the full sha of the commit that last changed the lines.
+	Sha string `json:"sha"`
+
+	// Author is the github login of the author if known, otherwise the name
+	// of the author.
+	Author string `json:"author"`
+
+	// Date is when the author made the change.
+	Date time.Time `json:"date"`
+}
+
+// Blame returns who last changed each line of the file at the path in ranges
+// of lines ordered by line.  The path must be in a tree like
+// org/repo/git/main/CODEOWNERS, and the blame is of the commit the tree is
+// read at.
+func (gfs *FS) Blame(ctx context.Context, name string) ([]BlameRange, error) {
+	if !fs.ValidPath(name) {
+		return nil, fmt.Errorf("blame %s %w", name, fs.ErrInvalid)
+	}
+
+	if err := gfs.connect(); err != nil {
+		return nil, fmt.Errorf("blame %s error connecting: %w", name, err)
+	}
+
+	ranges, err := gfs.blame(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("blame %s %w", name, err)
+	}
+
+	return ranges, nil
+}
+
+// blame fetches the blame of the file at the path from github.
+func (gfs *FS) blame(ctx context.Context, name string) ([]BlameRange, error) {
+	tree, rel, err := gfs.findTree(name)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]any{
+		"owner": tree.org,
+		"repo":  tree.repo,
+		"exp":   tree.rev(),
+		"path":  rel,
+	}
+
+	/*
+		query {
+		  repository(name: "repo", owner: "org") {
+		    object(expression: "main") {
+		      ... on Commit {
+		        blame(path: "CODEOWNERS") {
+		          ranges {
+		            startingLine
+		            endingLine
+		            commit {
+		              oid
+		              author {
+		                name
+		                email
+		                date
+		                user {
+		                  login
+		                }
+		              }
+		            }
+		          }
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	var query struct {
+		Repository struct {
+			Object struct {
+				Commit struct {
+					Blame struct {
+						Ranges []struct {
+							StartingLine int
+							EndingLine   int
+							Commit       struct {
+								Oid    string
+								Author gitActor
+							}
+						}
+					} `graphql:"blame(path: $path)"`
+				} `graphql:"... on Commit"`
+			} `graphql:"object(expression: $exp)"`
+		} `graphql:"repository(name: $repo, owner: $owner)"`
+	}
+
+	if err := gfs.gqlClient.Query(ctx, &query, vars); err != nil {
+		return nil, err
+	}
+
+	ranges := make([]BlameRange, 0, len(query.Repository.Object.Commit.Blame.Ranges))
+	for _, r := range query.Repository.Object.Commit.Blame.Ranges {
+		ranges = append(ranges, BlameRange{
+			StartLine: r.StartingLine,
+			EndLine:   r.EndingLine,
+			Sha:       r.Commit.Oid,
+			Author:    r.Commit.Author.login(),
+			Date:      r.Commit.Author.Date,
+		})
+	}
+
+	return ranges, nil
+}
+
+// splitBlame returns the path of the file a blame file is for.
+func (gfs *FS) splitBlame(name string) (string, bool) {
+	if !gfs.blameFiles || !strings.HasSuffix(name, blameExt) {
+		return "", false
+	}
+	return strings.TrimSuffix(name, blameExt), true
+}
+
+// openBlame fetches the blame of the file at the path.  The blame isn't added
+// to the directory of the file so it isn't listed with the files of the repo.
+func (gfs *FS) openBlame(name string) (fs.File, error) {
+	d, f, err := gfs.root.find(name)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, fmt.Errorf("%s is not a file %w", name, fs.ErrNotExist)
+	}
+
+	ranges, err := gfs.blame(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := json.MarshalIndent(ranges, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var latest time.Time
+	for _, r := range ranges {
+		if r.Date.After(latest) {
+			latest = r.Date
+		}
+	}
+
+	return newFile(d, path.Base(name)+blameExt, withContent(buf), withModTime(latest)).newFileHandle()
+}
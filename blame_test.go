@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlame(t *testing.T) {
+	tests := []struct {
+		description string
+		path        string
+		payload     []string
+		expect      []BlameRange
+		bodies      []string
+		expectErr   bool
+		errIs       error
+	}{
+		{
+			description: "blame a file",
+			path:        "org/repo/git/main/config.yml",
+			payload:     []string{singleRepoWithHeadReponse, blameResponse},
+			expect: []BlameRange{
+				{
+					StartLine: 1,
+					EndLine:   2,
+					Sha:       "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+					Author:    "monalisa",
+					Date:      time.Date(2023, 5, 2, 10, 0, 0, 0, time.UTC),
+				}, {
+					StartLine: 3,
+					EndLine:   3,
+					Sha:       "cccccccccccccccccccccccccccccccccccccccc",
+					Author:    "Mona Lisa",
+					Date:      time.Date(2023, 4, 1, 10, 0, 0, 0, time.UTC),
+				},
+			},
+			bodies: []string{
+				`"exp":"1111111111111111111111111111111111111111"`,
+				`"path":"config.yml"`,
+			},
+		}, {
+			description: "not a file in a tree",
+			path:        "org/repo/commits",
+			payload:     []string{singleRepoWithHeadReponse},
+			expectErr:   true,
+			errIs:       fs.ErrNotExist,
+		}, {
+			description: "a file pinned to a commit, but not in a tree",
+			path:        "org/repo/history/main/cccccccccccccccccccccccccccccccccccccccc/commit.json",
+			payload:     []string{singleRepoWithHeadReponse},
+			expectErr:   true,
+			errIs:       fs.ErrNotExist,
+		}, {
+			description: "an invalid path",
+			path:        "/org/repo",
+			expectErr:   true,
+			errIs:       fs.ErrInvalid,
+		}, {
+			description: "blame a file, but there was a json error",
+			path:        "org/repo/git/main/config.yml",
+			payload:     []string{singleRepoWithHeadReponse, invalidJsonResponse},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gfs, _, bodies := newTestFS(t, fsTest{
+				opts:    []Option{WithRepo("org", "repo")},
+				payload: tc.payload,
+			})
+
+			got, err := gfs.Blame(context.Background(), tc.path)
+			if tc.expectErr {
+				assert.Error(err)
+				if tc.errIs != nil {
+					assert.ErrorIs(err, tc.errIs)
+				}
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tc.expect, got)
+			for _, body := range tc.bodies {
+				assert.Contains(strings.Join(*bodies, "\n"), body)
+			}
+		})
+	}
+}
+
+func TestBlameFiles(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "read the blame of a file",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0), WithBlameFiles()},
+			payload:     []string{singleRepoWithHeadReponse, baseDirectoryResponse, blameResponse},
+			contents: map[string]string{
+				"org/repo/git/main/README.md.blame.json": blameFileContent,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"POST /",
+			},
+		}, {
+			description: "blame files are only available when enabled",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoWithHeadReponse, baseDirectoryResponse},
+			unexpected:  []string{"org/repo/git/main/README.md.blame.json"},
+			requests: []string{
+				"POST /",
+				"POST /",
+			},
+		}, {
+			description: "the blame of a file that doesn't exist",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0), WithBlameFiles()},
+			payload:     []string{singleRepoWithHeadReponse, baseDirectoryResponse},
+			unexpected:  []string{"org/repo/git/main/missing.yml.blame.json"},
+			requests: []string{
+				"POST /",
+				"POST /",
+			},
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+func TestBlameFilesNotListed(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, _, _ := newTestFS(t, fsTest{
+		opts:    []Option{WithRepo("org", "repo"), WithThresholdInKB(0), WithBlameFiles()},
+		payload: []string{singleRepoWithHeadReponse, baseDirectoryResponse, blameResponse},
+	})
+
+	_, err := fs.ReadFile(gfs, "org/repo/git/main/README.md.blame.json")
+	require.NoError(err)
+
+	entries, err := fs.ReadDir(gfs, "org/repo/git/main")
+	require.NoError(err)
+	for _, entry := range entries {
+		assert.NotEqual("README.md.blame.json", entry.Name())
+	}
+}
+
+var blameResponse = `{
+  "data": {
+    "repository": {
+      "object": {
+        "blame": {
+          "ranges": [
+            {
+              "startingLine": 1,
+              "endingLine": 2,
+              "commit": {
+                "oid": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+                "author": {
+                  "name": "Mona Lisa",
+                  "email": "mona@example.com",
+                  "date": "2023-05-02T10:00:00Z",
+                  "user": {
+                    "login": "monalisa"
+                  }
+                }
+              }
+            },
+            {
+              "startingLine": 3,
+              "endingLine": 3,
+              "commit": {
+                "oid": "cccccccccccccccccccccccccccccccccccccccc",
+                "author": {
+                  "name": "Mona Lisa",
+                  "email": "mona@example.com",
+                  "date": "2023-04-01T10:00:00Z",
+                  "user": null
+                }
+              }
+            }
+          ]
+        }
+      }
+    }
+  }
+}`
+
+var blameFileContent = `[
+  {
+    "startLine": 1,
+    "endLine": 2,
+    "sha": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+    "author": "monalisa",
+    "date": "2023-05-02T10:00:00Z"
+  },
+  {
+    "startLine": 3,
+    "endLine": 3,
+    "sha": "cccccccccccccccccccccccccccccccccccccccc",
+    "author": "Mona Lisa",
+    "date": "2023-04-01T10:00:00Z"
+  }
+]`
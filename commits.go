@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+const (
+	minShaLen = 4
+	maxShaLen = 40
+)
+
+// isSha determines if the name looks like a full or abbreviated commit sha.
+func isSha(name string) bool {
+	if len(name) < minShaLen || len(name) > maxShaLen {
+		return false
+	}
+	return strings.Trim(strings.ToLower(name), "0123456789abcdef") == ""
+}
+
+// lookupCommit resolves a full or abbreviated commit sha against github and
+// adds a directory pinned to the commit.  The sha of an annotated tag is
+// resolved to the commit it tags.  The contents of a commit never change, so
+// the directory is kept for the life of the filesystem.
+func lookupCommit(gfs *FS, d *dir, name string) error {
+	if !isSha(name) {
+		return fmt.Errorf("commit %s is not a sha %w", name, fs.ErrNotExist)
+	}
+
+	oid, err := resolveCommit(context.Background(), gfs, d.org, d.repo, name)
+	if err != nil {
+		return err
+	}
+
+	d.newDir(name, withCommit(oid), notInPath(), gfs.treeFetcher(d.size))
+	return nil
+}
+
+// resolveCommit asks github for the full commit sha the expression refers to.
+// Annotated tags are peeled to the commit they tag.
+func resolveCommit(ctx context.Context, gfs *FS, org, repo, exp string) (string, error) {
+	vars := map[string]any{
+		"owner": org,
+		"repo":  repo,
+		"exp":   exp,
+	}
+
+	/*
+		query {
+		  repository(name: "repo", owner: "org") {
+		    object(expression: "4b825dc") {
+		      ... on Commit {
+		        oid
+		      }
+		      ... on Tag {
+		        target {
+		          ... on Commit {
+		            oid
+		          }
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	var query struct {
+		Repository struct {
+			Object struct {
+				Commit struct {
+					Oid string
+				} `graphql:"... on Commit"`
+				Tag struct {
+					Target struct {
+						Commit struct {
+							Oid string
+						} `graphql:"... on Commit"`
+					}
+				} `graphql:"... on Tag"`
+			} `graphql:"object(expression: $exp)"`
+		} `graphql:"repository(name: $repo, owner: $owner)"`
+	}
+
+	if err := gfs.gqlClient.Query(ctx, &query, vars); err != nil {
+		return "", err
+	}
+
+	// An annotated tag points at the commit it tags.
+	oid := query.Repository.Object.Commit.Oid
+	if len(oid) == 0 {
+		oid = query.Repository.Object.Tag.Target.Commit.Oid
+	}
+	if len(oid) == 0 {
+		return "", fmt.Errorf("commit %s not found %w", exp, fs.ErrNotExist)
+	}
+
+	return oid, nil
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSha(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect bool
+	}{
+		{name: "4b82", expect: true},
+		{name: "4B825DC", expect: true},
+		{name: "4b825dc642cb6eb9a060e54bf8d69288fbee4904", expect: true},
+		{name: "4b8"},
+		{name: "4b825dc642cb6eb9a060e54bf8d69288fbee49041"},
+		{name: "main"},
+		{name: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, isSha(tc.name))
+		})
+	}
+}
+
+func TestCommits(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "fetch a commit all at once",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse, commitResponse, commitTarballResponse, fullRepoTarball},
+			expect:      []string{"org/repo/commits/4b825dc", "org/repo/commits/4b825dc/a"},
+		}, {
+			description: "fetch a commit a file at a time",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoReponse, commitResponse, baseDirectoryResponse, readmeResponse},
+			contents: map[string]string{
+				"org/repo/commits/4b825dc/README.md": readmeResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"POST /",
+				"GET /org/repo/4b825dc642cb6eb9a060e54bf8d69288fbee4904/README.md",
+			},
+		}, {
+			description: "a commit is only resolved once",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoReponse, commitResponse, baseDirectoryResponse},
+			expect:      []string{"org/repo/commits/4b825dc", "org/repo/commits/4b825dc", "org/repo/commits"},
+		}, {
+			description: "the sha of an annotated tag is the commit it tags",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoReponse, annotatedTagResponse, baseDirectoryResponse, readmeResponse},
+			contents: map[string]string{
+				"org/repo/commits/abcdef0/README.md": readmeResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"POST /",
+				"GET /org/repo/2222222222222222222222222222222222222222/README.md",
+			},
+		}, {
+			description: "a name that is not a sha",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse},
+			unexpected:  []string{"org/repo/commits/main"},
+			requests:    []string{"POST /"},
+		}, {
+			description: "a sha that is not found",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse, commitNotFoundResponse},
+			unexpected:  []string{"org/repo/commits/abcdef0"},
+		}, {
+			description: "resolve a commit, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse, invalidJsonResponse},
+			expect:      []string{"org/repo/commits/4b825dc"},
+			expectErr:   true,
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+var commitResponse = `{
+  "data": {
+    "repository": {
+      "object": {
+        "oid": "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+      }
+    }
+  }
+}`
+
+var commitNotFoundResponse = `{
+  "data": {
+    "repository": {
+      "object": null
+    }
+  }
+}`
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+const (
+	compareSeparator = "..."
+	compareExt       = ".patch"
+
+	// maxComparisons is the most comparisons cached.  The oldest is dropped
+	// to make room for a new one.
+	maxComparisons = 100
+)
+
+// ChangedFile is a file changed between two commits.
+type ChangedFile struct {
+	// Path is the path of the file in the head commit.
+	Path string
+
+	// PreviousPath is the path of the file in the base commit if the file
+	// was renamed.
+	PreviousPath string
+
+	// Status is how the file changed: added, removed, modified, renamed,
+	// copied, changed or unchanged.
+	Status string
+
+	// Additions is the number of lines added.
+	Additions int
+
+	// Deletions is the number of lines removed.
+	Deletions int
+}
+
+// Comparison is the difference between two commits of a repository.
+type Comparison struct {
+	// Base is the full sha of the base commit.
+	Base string
+
+	// Head is the full sha of the head commit.
+	Head string
+
+	// MergeBase is the full sha of the best common ancestor of the commits.
+	MergeBase string
+
+	// Files are the files that changed.
+	Files []ChangedFile
+
+	// Patch is the unified diff of the changes.
+	Patch string
+}
+
+// Diff compares the base and head of the repository in the "org/repo" form
+// using the github compare API.  The base and head may be branches, tags or
+// commits.  Branches of the filesystem are compared at the commits they are
+// pinned to.  The comparison of two commits never changes, so the most recent
+// results are cached by the commits compared.
+//
+// The unified diff is also available as org/repo/compare/<base>...<head>.patch
+// for refs without a '/' in their names.
+func (gfs *FS) Diff(ctx context.Context, repo, base, head string) (*Comparison, error) {
+	org, name, found := strings.Cut(repo, "/")
+	if !found || len(org) == 0 || len(name) == 0 || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("diff %s %w", repo, fs.ErrInvalid)
+	}
+
+	if err := gfs.connect(); err != nil {
+		return nil, fmt.Errorf("diff %s error connecting: %w", repo, err)
+	}
+
+	c, err := gfs.compare(ctx, org, name, base, head)
+	if err != nil {
+		return nil, fmt.Errorf("diff %s %w", repo, err)
+	}
+
+	return c, nil
+}
+
+// compare resolves the base and head to commits and returns the cached
+// comparison of the commits, or asks github for it.
+func (gfs *FS) compare(ctx context.Context, org, repo, base, head string) (*Comparison, error) {
+	baseSha, err := gfs.resolveRef(ctx, org, repo, base)
+	if err != nil {
+		return nil, err
+	}
+	headSha, err := gfs.resolveRef(ctx, org, repo, head)
+	if err != nil {
+		return nil, err
+	}
+
+	key := org + "/" + repo + ":" + baseSha + compareSeparator + headSha
+
+	gfs.comparesLock.Lock()
+	c, found := gfs.compares[key]
+	gfs.comparesLock.Unlock()
+	if found {
+		return c, nil
+	}
+
+	u := strings.Join([]string{gfs.restUrl, "repos", org, repo, "compare", baseSha + compareSeparator + headSha}, "/")
+
+	var resp struct {
+		MergeBaseCommit struct {
+			Sha string `json:"sha"`
+		} `json:"merge_base_commit"`
+		Files []struct {
+			Filename         string `json:"filename"`
+			PreviousFilename string `json:"previous_filename"`
+			Status           string `json:"status"`
+			Additions        int    `json:"additions"`
+			Deletions        int    `json:"deletions"`
+		} `json:"files"`
+	}
+	if err = restGet(ctx, gfs, u, &resp); err != nil {
+		return nil, err
+	}
+
+	patch, err := download(ctx, gfs, u, "application/vnd.github.diff")
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Comparison{
+		Base:      baseSha,
+		Head:      headSha,
+		MergeBase: resp.MergeBaseCommit.Sha,
+		Files:     make([]ChangedFile, 0, len(resp.Files)),
+		Patch:     string(patch),
+	}
+	for _, f := range resp.Files {
+		c.Files = append(c.Files, ChangedFile{
+			Path:         f.Filename,
+			PreviousPath: f.PreviousFilename,
+			Status:       f.Status,
+			Additions:    f.Additions,
+			Deletions:    f.Deletions,
+		})
+	}
+
+	gfs.cacheComparison(key, c)
+
+	return c, nil
+}
+
+// cacheComparison caches the comparison, dropping the oldest one if the cache
+// is full.
+func (gfs *FS) cacheComparison(key string, c *Comparison) {
+	gfs.comparesLock.Lock()
+	defer gfs.comparesLock.Unlock()
+
+	if _, found := gfs.compares[key]; !found {
+		if len(gfs.compareKeys) >= maxComparisons {
+			delete(gfs.compares, gfs.compareKeys[0])
+			gfs.compareKeys = gfs.compareKeys[1:]
+		}
+		gfs.compareKeys = append(gfs.compareKeys, key)
+	}
+	gfs.compares[key] = c
+}
+
+// resolveRef resolves a ref to the commit it points to.  Branches that are
+// pinned resolve to the pinned commit, and full shas are already commits.
+func (gfs *FS) resolveRef(ctx context.Context, org, repo, ref string) (string, error) {
+	if len(ref) == maxShaLen && isSha(ref) {
+		return ref, nil
+	}
+	key := lockKey(org, repo, ref)
+	if oid, found := gfs.lock[key]; found {
+		return oid, nil
+	}
+	if oid, found := gfs.pins[key]; found {
+		return oid, nil
+	}
+
+	return resolveCommit(ctx, gfs, org, repo, ref)
+}
+
+// lookupCompare resolves a "<base>...<head>.patch" name in the compare
+// directory to the unified diff between the base and head.
+func lookupCompare(gfs *FS, d *dir, name string) error {
+	base, head, found := strings.Cut(strings.TrimSuffix(name, compareExt), compareSeparator)
+	if !strings.HasSuffix(name, compareExt) || !found || len(base) == 0 || len(head) == 0 {
+		return fmt.Errorf("%s is not a comparison %w", name, fs.ErrNotExist)
+	}
+
+	c, err := gfs.compare(context.Background(), d.org, d.repo, base, head)
+	if err != nil {
+		return err
+	}
+
+	d.addFile(name, withContent([]byte(c.Patch)))
+	return nil
+}
@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		description string
+		repo        string
+		base        string
+		head        string
+		payload     []string
+		statusCode  []int
+		expect      *Comparison
+		requests    []string
+		expectErr   bool
+		errIs       error
+	}{
+		{
+			description: "compare a tag with a pinned branch",
+			repo:        "org/repo",
+			base:        "v1.0",
+			head:        "main",
+			payload:     []string{singleRepoWithHeadReponse, commitResponse, compareResponse, pullDiffResponse},
+			expect: &Comparison{
+				Base:      "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+				Head:      "1111111111111111111111111111111111111111",
+				MergeBase: "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+				Files: []ChangedFile{
+					{
+						Path:      "config.yml",
+						Status:    "modified",
+						Additions: 1,
+					}, {
+						Path:         "docs/README.md",
+						PreviousPath: "README.md",
+						Status:       "renamed",
+					},
+				},
+				Patch: pullDiffResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"GET /repos/org/repo/compare/4b825dc642cb6eb9a060e54bf8d69288fbee4904...1111111111111111111111111111111111111111",
+				"GET /repos/org/repo/compare/4b825dc642cb6eb9a060e54bf8d69288fbee4904...1111111111111111111111111111111111111111",
+			},
+		}, {
+			description: "compare an annotated tag",
+			repo:        "org/repo",
+			base:        "v1.1",
+			head:        "main",
+			payload:     []string{singleRepoWithHeadReponse, annotatedTagResponse, compareResponse, pullDiffResponse},
+			expect: &Comparison{
+				Base:      "2222222222222222222222222222222222222222",
+				Head:      "1111111111111111111111111111111111111111",
+				MergeBase: "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+				Files: []ChangedFile{
+					{
+						Path:      "config.yml",
+						Status:    "modified",
+						Additions: 1,
+					}, {
+						Path:         "docs/README.md",
+						PreviousPath: "README.md",
+						Status:       "renamed",
+					},
+				},
+				Patch: pullDiffResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"GET /repos/org/repo/compare/2222222222222222222222222222222222222222...1111111111111111111111111111111111111111",
+				"GET /repos/org/repo/compare/2222222222222222222222222222222222222222...1111111111111111111111111111111111111111",
+			},
+		}, {
+			description: "an invalid repo",
+			repo:        "org",
+			expectErr:   true,
+			errIs:       fs.ErrInvalid,
+		}, {
+			description: "a ref that doesn't exist",
+			repo:        "org/repo",
+			base:        "v9.9",
+			head:        "main",
+			payload:     []string{singleRepoWithHeadReponse, commitNotFoundResponse},
+			expectErr:   true,
+			errIs:       fs.ErrNotExist,
+		}, {
+			description: "the compare api fails",
+			repo:        "org/repo",
+			base:        "main",
+			head:        "main",
+			statusCode:  []int{0, 500},
+			payload:     []string{singleRepoWithHeadReponse},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			gfs, requests, _ := newTestFS(t, fsTest{
+				opts:       []Option{WithRepo("org", "repo")},
+				payload:    tc.payload,
+				statusCode: tc.statusCode,
+			})
+
+			got, err := gfs.Diff(context.Background(), tc.repo, tc.base, tc.head)
+			if tc.expectErr {
+				assert.Error(err)
+				if tc.errIs != nil {
+					assert.ErrorIs(err, tc.errIs)
+				}
+				return
+			}
+
+			require.NoError(err)
+			assert.Equal(tc.expect, got)
+
+			// The same commits by sha come from the cache.
+			again, err := gfs.Diff(context.Background(), tc.repo, got.Base, got.Head)
+			require.NoError(err)
+			assert.Same(got, again)
+			assert.Equal(tc.requests, *requests)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "read the diff between two refs",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithHeadReponse, commitResponse, compareResponse, pullDiffResponse},
+			contents: map[string]string{
+				"org/repo/compare/v1.0...main.patch": pullDiffResponse,
+			},
+		}, {
+			description: "a name that isn't a comparison",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithHeadReponse},
+			unexpected: []string{
+				"org/repo/compare/v1.0..main.patch",
+				"org/repo/compare/v1.0...main.diff",
+				"org/repo/compare/...main.patch",
+			},
+			requests: []string{"POST /"},
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+func TestCacheComparison(t *testing.T) {
+	assert := assert.New(t)
+
+	gfs := New()
+	for i := 0; i <= maxComparisons; i++ {
+		key := fmt.Sprint(i)
+		gfs.cacheComparison(key, &Comparison{Base: key})
+	}
+
+	// The oldest comparison made room for the newest.
+	assert.Len(gfs.compares, maxComparisons)
+	assert.Len(gfs.compareKeys, maxComparisons)
+	assert.NotContains(gfs.compares, "0")
+	assert.Contains(gfs.compares, "1")
+	assert.Contains(gfs.compares, fmt.Sprint(maxComparisons))
+
+	// Caching the same comparison again doesn't drop another.
+	gfs.cacheComparison("1", &Comparison{Base: "1"})
+	assert.Len(gfs.compares, maxComparisons)
+	assert.Len(gfs.compareKeys, maxComparisons)
+	assert.Contains(gfs.compares, "2")
+}
+
+var annotatedTagResponse = `{
+  "data": {
+    "repository": {
+      "object": {
+        "target": {
+          "oid": "2222222222222222222222222222222222222222"
+        }
+      }
+    }
+  }
+}`
+
+var compareResponse = `{
+  "status": "ahead",
+  "ahead_by": 2,
+  "behind_by": 0,
+  "merge_base_commit": {
+    "sha": "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+  },
+  "files": [
+    {
+      "filename": "config.yml",
+      "status": "modified",
+      "additions": 1,
+      "deletions": 0
+    },
+    {
+      "filename": "docs/README.md",
+      "previous_filename": "README.md",
+      "status": "renamed",
+      "additions": 0,
+      "deletions": 0
+    }
+  ]
+}`
@@ -0,0 +1,295 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	diffContext  = 3
+	diffNewline  = "\\ No newline at end of file\n"
+	diffNullPath = "/dev/null"
+	diffFileMode = "100644"
+	diffExecMode = "100755"
+	diffAbbrev   = 7
+
+	// diffMaxEdits limits the number of edits searched for.  The memory used
+	// grows with the square of the edits, so files that differ by more are
+	// diffed as a whole file replace.
+	diffMaxEdits = 1000
+)
+
+// diffOp is one line of an edit script: a line kept from both sides (' '),
+// removed from the old side ('-') or added from the new side ('+').
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// fileDiff is the change to one file.  A file that didn't exist has no old
+// contents, and a deleted file has no new contents.
+type fileDiff struct {
+	path    string
+	old     []byte
+	new     []byte
+	created bool
+	deleted bool
+	exec    bool
+}
+
+// write writes the change in the git diff format and returns the number of
+// lines added and removed.  Nothing is written if the contents are the same.
+func (fd fileDiff) write(b *strings.Builder) (int, int) {
+	ops := diffLines(splitLines(string(fd.old)), splitLines(string(fd.new)))
+
+	var adds, dels int
+	for _, op := range ops {
+		switch op.kind {
+		case '+':
+			adds++
+		case '-':
+			dels++
+		}
+	}
+	if adds == 0 && dels == 0 && !fd.created && !fd.deleted {
+		return 0, 0
+	}
+
+	fmt.Fprintf(b, "diff --git a/%s b/%s\n", fd.path, fd.path)
+	mode := diffFileMode
+	if fd.exec {
+		mode = diffExecMode
+	}
+	oldName, newName := "a/"+fd.path, "b/"+fd.path
+	index := fmt.Sprintf("index %s..%s", blobSha(fd.old, !fd.created), blobSha(fd.new, !fd.deleted))
+	switch {
+	case fd.created:
+		fmt.Fprintf(b, "new file mode %s\n%s\n", mode, index)
+		oldName = diffNullPath
+	case fd.deleted:
+		fmt.Fprintf(b, "deleted file mode %s\n%s\n", mode, index)
+		newName = diffNullPath
+	default:
+		fmt.Fprintf(b, "%s %s\n", index, mode)
+	}
+
+	if adds == 0 && dels == 0 {
+		return 0, 0
+	}
+
+	fmt.Fprintf(b, "--- %s\n+++ %s\n", oldName, newName)
+	writeHunks(b, ops)
+
+	return adds, dels
+}
+
+// blobSha returns the abbreviated git blob sha of the contents, or zeros if
+// there is no file.
+func blobSha(content []byte, exists bool) string {
+	if !exists {
+		return strings.Repeat("0", diffAbbrev)
+	}
+
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))[:diffAbbrev]
+}
+
+// splitLines splits the text into lines that keep their newlines, so a last
+// line without a newline is different from the same line with one.
+func splitLines(s string) []string {
+	var lines []string
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			lines = append(lines, s)
+			break
+		}
+		lines = append(lines, s[:i+1])
+		s = s[i+1:]
+	}
+	return lines
+}
+
+// diffLines finds the shortest edit script that turns the old lines into the
+// new lines using the Myers algorithm.  If the script needs more than
+// diffMaxEdits edits every old line is removed and every new line is added
+// instead.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	most := n + m
+	if most > diffMaxEdits {
+		most = diffMaxEdits
+	}
+	off := most + 1
+	v := make([]int, 2*most+3)
+
+	// Find the furthest reaching path for each number of edits, keeping the
+	// diagonals each step reads so the path can be walked back.  Step d only
+	// reads the diagonals from -d to d.
+	var trace [][]int
+	done := false
+	for d := 0; d <= most && !done; d++ {
+		trace = append(trace, append([]int{}, v[off-d:off+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+	}
+	if !done {
+		return replaceLines(a, b)
+	}
+
+	// Walk back from the end to build the script in reverse.
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[k-1+d] < v[k+1+d]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = v[prevK+d]
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{kind: ' ', line: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{kind: '+', line: b[y-1]})
+			} else {
+				ops = append(ops, diffOp{kind: '-', line: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+// replaceLines is the edit script that removes all of the old lines and adds
+// all of the new lines.
+func replaceLines(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a {
+		ops = append(ops, diffOp{kind: '-', line: line})
+	}
+	for _, line := range b {
+		ops = append(ops, diffOp{kind: '+', line: line})
+	}
+	return ops
+}
+
+// writeHunks writes the changes in the edit script as hunks with lines of
+// context around them.  Changes close enough to share context are written as
+// one hunk.
+func writeHunks(b *strings.Builder, ops []diffOp) {
+	// The line of each side each operation starts at.
+	aLine := make([]int, len(ops)+1)
+	bLine := make([]int, len(ops)+1)
+	for i, op := range ops {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if op.kind != '+' {
+			aLine[i+1]++
+		}
+		if op.kind != '-' {
+			bLine[i+1]++
+		}
+	}
+
+	i := 0
+	for {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			return
+		}
+
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend the hunk until the unchanged lines are too many to share.
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContext {
+				break
+			}
+			end = run
+		}
+		stop := end + diffContext
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		fmt.Fprintf(b, "@@ -%s +%s @@\n",
+			hunkRange(aLine[start], aLine[stop]-aLine[start]),
+			hunkRange(bLine[start], bLine[stop]-bLine[start]))
+		for _, op := range ops[start:stop] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				b.WriteString("\n" + diffNewline)
+			}
+		}
+
+		i = stop
+	}
+}
+
+// hunkRange formats the start and number of lines of one side of a hunk.  The
+// start counts from 1, or is the line before the hunk if it has no lines.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileDiff(t *testing.T) {
+	tests := []struct {
+		description string
+		fd          fileDiff
+		expect      string
+		adds        int
+		dels        int
+	}{
+		{
+			description: "the same contents",
+			fd: fileDiff{
+				path: "a.txt",
+				old:  []byte("one\ntwo\n"),
+				new:  []byte("one\ntwo\n"),
+			},
+		}, {
+			description: "change a line",
+			fd: fileDiff{
+				path: "a.txt",
+				old:  []byte("1\n2\n3\n4\n5\n6\n7\n8\n"),
+				new:  []byte("1\n2\n3\n4\nfive\n6\n7\n8\n"),
+			},
+			expect: "diff --git a/a.txt b/a.txt\n" +
+				"index 535d2b0..ab00c61 100644\n" +
+				"--- a/a.txt\n" +
+				"+++ b/a.txt\n" +
+				"@@ -2,7 +2,7 @@\n" +
+				" 2\n" +
+				" 3\n" +
+				" 4\n" +
+				"-5\n" +
+				"+five\n" +
+				" 6\n" +
+				" 7\n" +
+				" 8\n",
+			adds: 1,
+			dels: 1,
+		}, {
+			description: "changes far apart are separate hunks",
+			fd: fileDiff{
+				path: "a.txt",
+				old:  []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"),
+				new:  []byte("one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n"),
+			},
+			expect: "diff --git a/a.txt b/a.txt\n" +
+				"index f00c965..c261f35 100644\n" +
+				"--- a/a.txt\n" +
+				"+++ b/a.txt\n" +
+				"@@ -1,4 +1,4 @@\n" +
+				"-1\n" +
+				"+one\n" +
+				" 2\n" +
+				" 3\n" +
+				" 4\n" +
+				"@@ -7,4 +7,4 @@\n" +
+				" 7\n" +
+				" 8\n" +
+				" 9\n" +
+				"-10\n" +
+				"+ten\n",
+			adds: 2,
+			dels: 2,
+		}, {
+			description: "a new file without a newline at the end",
+			fd: fileDiff{
+				path:    "dir/b.txt",
+				new:     []byte("hello"),
+				created: true,
+			},
+			expect: "diff --git a/dir/b.txt b/dir/b.txt\n" +
+				"new file mode 100644\n" +
+				"index 0000000..b6fc4c6\n" +
+				"--- /dev/null\n" +
+				"+++ b/dir/b.txt\n" +
+				"@@ -0,0 +1 @@\n" +
+				"+hello\n" +
+				"\\ No newline at end of file\n",
+			adds: 1,
+		}, {
+			description: "a deleted file",
+			fd: fileDiff{
+				path:    "c.txt",
+				old:     []byte("a\nb\n"),
+				deleted: true,
+			},
+			expect: "diff --git a/c.txt b/c.txt\n" +
+				"deleted file mode 100644\n" +
+				"index 422c2b7..0000000\n" +
+				"--- a/c.txt\n" +
+				"+++ /dev/null\n" +
+				"@@ -1,2 +0,0 @@\n" +
+				"-a\n" +
+				"-b\n",
+			dels: 2,
+		}, {
+			description: "a deleted executable",
+			fd: fileDiff{
+				path:    "run.sh",
+				old:     []byte("a\n"),
+				deleted: true,
+				exec:    true,
+			},
+			expect: "diff --git a/run.sh b/run.sh\n" +
+				"deleted file mode 100755\n" +
+				"index 7898192..0000000\n" +
+				"--- a/run.sh\n" +
+				"+++ /dev/null\n" +
+				"@@ -1 +0,0 @@\n" +
+				"-a\n",
+			dels: 1,
+		}, {
+			description: "a new empty file",
+			fd: fileDiff{
+				path:    "empty",
+				created: true,
+			},
+			expect: "diff --git a/empty b/empty\n" +
+				"new file mode 100644\n" +
+				"index 0000000..e69de29\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var b strings.Builder
+			adds, dels := tc.fd.write(&b)
+
+			assert.Equal(tc.expect, b.String())
+			assert.Equal(tc.adds, adds)
+			assert.Equal(tc.dels, dels)
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	lines := func(prefix string, n int) []string {
+		var list []string
+		for i := 0; i < n; i++ {
+			list = append(list, fmt.Sprintf("%s%d\n", prefix, i))
+		}
+		return list
+	}
+
+	tests := []struct {
+		description string
+		a           []string
+		b           []string
+		edits       int
+	}{
+		{
+			description: "no changes",
+			a:           lines("", 10),
+			b:           lines("", 10),
+		}, {
+			description: "interleaved changes",
+			a:           []string{"a\n", "b\n", "c\n", "a\n", "b\n", "b\n", "a\n"},
+			b:           []string{"c\n", "b\n", "a\n", "b\n", "a\n", "c\n"},
+			edits:       5,
+		}, {
+			description: "everything added",
+			b:           lines("", 5),
+			edits:       5,
+		}, {
+			description: "too many changes is a whole file replace",
+			a:           append(lines("old", diffMaxEdits), "same\n"),
+			b:           append(lines("new", diffMaxEdits), "same\n"),
+			edits:       2*diffMaxEdits + 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			ops := diffLines(tc.a, tc.b)
+
+			var a, b []string
+			var edits int
+			for _, op := range ops {
+				if op.kind != '+' {
+					a = append(a, op.line)
+				}
+				if op.kind != '-' {
+					b = append(b, op.line)
+				}
+				if op.kind != ' ' {
+					edits++
+				}
+			}
+			assert.Equal(tc.a, a)
+			assert.Equal(tc.b, b)
+			assert.Equal(tc.edits, edits)
+		})
+	}
+}
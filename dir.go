@@ -25,11 +25,22 @@ type dir struct {
 	repo     string
 	name     string
 	branch   string
+	commit   string
+	size     int
 	path     []string
 	perm     os.FileMode
 	modTime  time.Time
+	sys      any
 	children map[string]any
 	fetchFn  func(*FS, *dir) error
+	lookupFn func(*FS, *dir, string) error
+
+	// Only set for the directories that hold a git tree.
+	tree bool
+
+	// Only used by the git directory when the branches are discovered.
+	allBranches bool
+	globs       []string
 }
 
 type dirOpt func(d *dir)
@@ -55,6 +66,20 @@ func withBranch(branch string) dirOpt {
 	}
 }
 
+// withCommit provides a way to pin the directory to a specific commit.
+func withCommit(oid string) dirOpt {
+	return func(d *dir) {
+		d.commit = oid
+	}
+}
+
+// withDiskUsage provides a way to set the size of the repository in KB.
+func withDiskUsage(size int) dirOpt {
+	return func(d *dir) {
+		d.size = size
+	}
+}
+
 // withFetcher provides a way to set a fetcher to populate the directory lazily.
 func withFetcher(fn func(*FS, *dir) error) dirOpt {
 	return func(d *dir) {
@@ -62,6 +87,14 @@ func withFetcher(fn func(*FS, *dir) error) dirOpt {
 	}
 }
 
+// withLookup provides a way to set a function that populates children that are
+// not listed by the directory on demand.
+func withLookup(fn func(*FS, *dir, string) error) dirOpt {
+	return func(d *dir) {
+		d.lookupFn = fn
+	}
+}
+
 // notInPath provides a way to exclude this directory from being used for general
 // path determination.  Generally only something done at the org/repo/git levels.
 func notInPath() dirOpt {
@@ -77,6 +110,13 @@ func withDirModTime(t time.Time) dirOpt {
 	}
 }
 
+// withDirSys provides a way to set the Sys() data of the directory.
+func withDirSys(sys any) dirOpt {
+	return func(d *dir) {
+		d.sys = sys
+	}
+}
+
 // newDir creates a new directory based on the specified filesystem.  Really
 // only useful when creating the root node.  Use (*dir).newDir() normally.
 func newDir(gfs *FS, name string, opts ...dirOpt) *dir {
@@ -104,6 +144,8 @@ func (d *dir) newDir(name string, opts ...dirOpt) *dir {
 		repo:     d.repo,
 		name:     name,
 		branch:   d.branch,
+		commit:   d.commit,
+		size:     d.size,
 		perm:     fs.ModeDir | 0755,
 		children: make(map[string]any),
 	}
@@ -142,6 +184,37 @@ func (d *dir) makeDirs(parts []string, opts ...dirOpt) *dir {
 	return rv
 }
 
+// rev returns the git revision the directory is read at.  This is the commit
+// the directory is pinned to if there is one, otherwise the branch.
+func (d *dir) rev() string {
+	if len(d.commit) > 0 {
+		return d.commit
+	}
+	return d.branch
+}
+
+// rawUrl returns the url used to download the named file in this directory
+// at the directory's git revision.
+func (d *dir) rawUrl(name string) string {
+	parts := []string{d.gfs.rawUrl, d.org, d.repo, d.rev()}
+	parts = append(parts, d.path...)
+	parts = append(parts, name)
+	return strings.Join(parts, "/")
+}
+
+// mkref makes the directories needed for a git ref name like "release/1.0"
+// and returns the leaf directory.  The options are only applied to the leaf
+// directory, the intermediate directories are excluded from the path and look
+// up children the same way this directory does.
+func (d *dir) mkref(name string, opts ...dirOpt) *dir {
+	parts := strings.Split(name, "/")
+	cur := d
+	for _, part := range parts[:len(parts)-1] {
+		cur = cur.mkdir(part, notInPath(), withLookup(d.lookupFn))
+	}
+	return cur.mkdir(parts[len(parts)-1], opts...)
+}
+
 // newDirHandle creates a new dirHandle and returns it.
 func (d *dir) newDirHandle() *dirHandle {
 	d.m.Lock()
@@ -170,6 +243,7 @@ func (d *dir) toFileInfo() *fileInfo {
 		size:    4096,
 		modTime: d.modTime,
 		mode:    d.perm,
+		sys:     d.sys,
 	}
 }
 
@@ -305,20 +379,49 @@ func (d *dir) fetch() error {
 	return nil
 }
 
+// lookup gives the directory a chance to populate a child that is not listed
+// by the directory.  Anything found stays cached in the directory.
+func (d *dir) lookup(name string) error {
+	if d.lookupFn != nil {
+		err := d.lookupFn(d.gfs, d, name)
+		if err != nil {
+			return fmt.Errorf("githubfs filesystem error can't lookup %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// child fetches the directory if needed and returns the named child, looking
+// it up if it isn't listed.
+func (d *dir) child(name string) (any, error) {
+	if err := d.fetch(); err != nil {
+		return nil, err
+	}
+
+	child, found := d.children[name]
+	if !found {
+		if err := d.lookup(name); err != nil {
+			return nil, err
+		}
+		child, found = d.children[name]
+	}
+	if !found {
+		return nil, fmt.Errorf("directory %s not found %w", name, fs.ErrNotExist)
+	}
+
+	return child, nil
+}
+
 // findDir finds either the exact directory, or the directory containing
 // the file specified.
 func (d *dir) find(path string) (*dir, *file, error) {
 	parts := strings.Split(path, "/")
 	cur := d
 	for i, part := range parts {
-		if err := cur.fetch(); err != nil {
+		child, err := cur.child(part)
+		if err != nil {
 			return nil, nil, err
 		}
-
-		child, found := cur.children[part]
-		if !found {
-			return nil, nil, fmt.Errorf("directory %s not found %w", part, fs.ErrNotExist)
-		}
 		if _, isFile := child.(*file); isFile {
 			if i+1 == len(parts) {
 				return cur, child.(*file), nil
@@ -335,6 +438,20 @@ func (d *dir) find(path string) (*dir, *file, error) {
 	return cur, nil, nil
 }
 
+// peek returns the directory at the path below this directory if it is
+// already known, without fetching or looking up anything, or nil.
+func (d *dir) peek(parts ...string) *dir {
+	cur := d
+	for _, part := range parts {
+		next, ok := cur.children[part].(*dir)
+		if !ok {
+			return nil
+		}
+		cur = next
+	}
+	return cur
+}
+
 // tarSplitPath cleans up the path by removing the leading directory and any
 // trailing '/' characters that could cause issues.
 func tarSplitPath(path string) []string {
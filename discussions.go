@@ -0,0 +1,297 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The page sizes of the discussions, their comments and the replies to the
+// comments fetched together.  Github rejects a query that could return more
+// than 500,000 nodes, counted as the product of the nested page sizes, so the
+// sizes are kept under that and the comments and replies that don't fit are
+// fetched with follow up queries.
+const (
+	discussionPageSize = 25
+	commentPageSize    = 50
+	replyPageSize      = 20
+)
+
+// discussionComment is a top level comment on a discussion with its replies.
+type discussionComment struct {
+	Id     string
+	Author struct {
+		Login string
+	}
+	Body      string
+	CreatedAt time.Time
+	IsAnswer  bool
+	Replies   issueComments `graphql:"replies(first: $replies)"`
+}
+
+// discussionComments is a page of comments on a discussion.
+type discussionComments struct {
+	PageInfo struct {
+		HasNextPage bool
+		EndCursor   string
+	}
+	Edges []struct {
+		Node discussionComment
+	}
+}
+
+// discussionThread is a top level comment and all of its replies.
+type discussionThread struct {
+	comment issueComment
+	answer  bool
+	replies []issueComment
+}
+
+// getDiscussionsDir fetches the discussions and makes each into a markdown file
+// in the directory of its category.  The file has the details in the front
+// matter followed by the body and the threaded comments.
+func getDiscussionsDir(gfs *FS, d *dir) error {
+	vars := map[string]any{
+		"owner":    d.org,
+		"repo":     d.repo,
+		"count":    discussionPageSize,
+		"comments": commentPageSize,
+		"replies":  replyPageSize,
+		"after":    (*string)(nil),
+	}
+
+	/*
+		query {
+		  repository(name: "repo", owner: "org") {
+		    discussions(first: 25, orderBy: {field: CREATED_AT, direction: DESC}) {
+		      edges {
+		        node {
+		          number
+		          title
+		          body
+		          url
+		          isAnswered
+		          createdAt
+		          updatedAt
+		          author {
+		            login
+		          }
+		          category {
+		            slug
+		          }
+		          comments(first: 50) {
+		            edges {
+		              node {
+		                id
+		                author {
+		                  login
+		                }
+		                body
+		                createdAt
+		                isAnswer
+		                replies(first: 20) {
+		                  edges {
+		                    node {
+		                      author {
+		                        login
+		                      }
+		                      body
+		                      createdAt
+		                    }
+		                  }
+		                }
+		              }
+		            }
+		          }
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	more := true
+	for more {
+		var query struct {
+			Repository struct {
+				Discussions struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+					Edges []struct {
+						Node struct {
+							Number     int
+							Title      string
+							Body       string
+							Url        string
+							IsAnswered bool
+							CreatedAt  time.Time
+							UpdatedAt  time.Time
+							Author     struct {
+								Login string
+							}
+							Category struct {
+								Slug string
+							}
+							Comments discussionComments `graphql:"comments(first: $comments)"`
+						}
+					}
+				} `graphql:"discussions(first: $count, after: $after, orderBy: {field: CREATED_AT, direction: DESC})"`
+			} `graphql:"repository(name: $repo, owner: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return err
+		}
+
+		for _, edge := range query.Repository.Discussions.Edges {
+			disc := edge.Node
+
+			threads, err := getDiscussionThreads(gfs, d, disc.Number, disc.Comments)
+			if err != nil {
+				return err
+			}
+
+			fm := frontMatter{
+				{key: "number", value: disc.Number},
+				{key: "title", value: disc.Title},
+				{key: "category", value: disc.Category.Slug},
+				{key: "author", value: disc.Author.Login},
+				{key: "answered", value: disc.IsAnswered},
+				{key: "url", value: disc.Url},
+				{key: "createdAt", value: disc.CreatedAt},
+				{key: "updatedAt", value: disc.UpdatedAt},
+			}
+
+			d.mkdir(disc.Category.Slug, notInPath()).
+				addFile(strconv.Itoa(disc.Number)+".md",
+					withContent(renderDiscussion(fm, disc.Body, threads)),
+					withModTime(disc.UpdatedAt))
+		}
+
+		more = query.Repository.Discussions.PageInfo.HasNextPage
+		vars["after"] = query.Repository.Discussions.PageInfo.EndCursor
+	}
+
+	return nil
+}
+
+// getDiscussionThreads collects the threads from the first page of comments on
+// a discussion, and fetches the rest of the comments and replies if there are
+// too many to fetch with the discussion.
+func getDiscussionThreads(gfs *FS, d *dir, number int, page discussionComments) ([]discussionThread, error) {
+	vars := map[string]any{
+		"owner":   d.org,
+		"repo":    d.repo,
+		"number":  number,
+		"count":   commentPageSize,
+		"replies": replyPageSize,
+		"after":   (*string)(nil),
+	}
+
+	var threads []discussionThread
+	for {
+		for _, edge := range page.Edges {
+			c := edge.Node
+			thread := discussionThread{
+				comment: issueComment{
+					Author:    c.Author,
+					Body:      c.Body,
+					CreatedAt: c.CreatedAt,
+				},
+				answer: c.IsAnswer,
+			}
+			for _, reply := range c.Replies.Edges {
+				thread.replies = append(thread.replies, reply.Node)
+			}
+			if c.Replies.PageInfo.HasNextPage {
+				rest, err := getDiscussionReplies(gfs, c.Id, c.Replies.PageInfo.EndCursor)
+				if err != nil {
+					return nil, err
+				}
+				thread.replies = append(thread.replies, rest...)
+			}
+			threads = append(threads, thread)
+		}
+
+		if !page.PageInfo.HasNextPage {
+			return threads, nil
+		}
+		vars["after"] = page.PageInfo.EndCursor
+
+		var query struct {
+			Repository struct {
+				Discussion struct {
+					Comments discussionComments `graphql:"comments(first: $count, after: $after)"`
+				} `graphql:"discussion(number: $number)"`
+			} `graphql:"repository(name: $repo, owner: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return nil, err
+		}
+		page = query.Repository.Discussion.Comments
+	}
+}
+
+// getDiscussionReplies fetches the rest of the replies to a comment that has
+// too many replies to fetch with the comment.
+func getDiscussionReplies(gfs *FS, id, after string) ([]issueComment, error) {
+	vars := map[string]any{
+		"id":    id,
+		"count": 100,
+		"after": &after,
+	}
+
+	var replies []issueComment
+	more := true
+	for more {
+		var query struct {
+			Node struct {
+				Comment struct {
+					Replies issueComments `graphql:"replies(first: $count, after: $after)"`
+				} `graphql:"... on DiscussionComment"`
+			} `graphql:"node(id: $id)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return nil, err
+		}
+
+		for _, edge := range query.Node.Comment.Replies.Edges {
+			replies = append(replies, edge.Node)
+		}
+
+		more = query.Node.Comment.Replies.PageInfo.HasNextPage
+		vars["after"] = query.Node.Comment.Replies.PageInfo.EndCursor
+	}
+
+	return replies, nil
+}
+
+// renderDiscussion renders the front matter, body and threaded comments into a
+// markdown file.  The replies are nested under the comment they reply to.
+func renderDiscussion(fm frontMatter, body string, threads []discussionThread) []byte {
+	var b strings.Builder
+
+	b.Write(renderIssue(fm, body, nil))
+	if len(threads) > 0 {
+		b.WriteString("\n## Comments\n")
+	}
+	for _, thread := range threads {
+		note := ""
+		if thread.answer {
+			note = "(answer)"
+		}
+		writeComment(&b, "###", thread.comment, note)
+		for _, reply := range thread.replies {
+			writeComment(&b, "####", reply, "")
+		}
+	}
+
+	return []byte(b.String())
+}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscussions(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "fetch the discussions",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload: []string{singleRepoWithDiscussionsReponse, discussionsResponse,
+				discussionRepliesResponse, discussionCommentsResponse},
+			expect: []string{"org/repo/discussions/q-a/4.md"},
+			contents: map[string]string{
+				"org/repo/discussions/rfcs/5.md": discussion5Markdown,
+			},
+			bodies: []string{`"id":"DC_1"`, `"number":5`, `"comments":50`, `"replies":20`},
+		}, {
+			description: "a repo without discussions enabled has no discussions directory",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse},
+			unexpected:  []string{"org/repo/discussions"},
+		}, {
+			description: "fetch the discussions, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithDiscussionsReponse, invalidJsonResponse},
+			expect:      []string{"org/repo/discussions/rfcs"},
+			expectErr:   true,
+		}, {
+			description: "fetch the discussion replies, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithDiscussionsReponse, discussionsResponse, invalidJsonResponse},
+			expect:      []string{"org/repo/discussions/rfcs"},
+			expectErr:   true,
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+// maxQueryNodes is the most nodes github allows a query to return.
+const maxQueryNodes = 500000
+
+func TestDiscussionsNodeLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.LessOrEqual(discussionPageSize*commentPageSize*replyPageSize, maxQueryNodes)
+	assert.LessOrEqual(commentPageSize*replyPageSize, maxQueryNodes)
+}
+
+var singleRepoWithDiscussionsReponse = `{
+  "data": {
+    "repository": {
+      "diskUsage": 18,
+      "isArchived": false,
+      "isDisabled": false,
+      "hasDiscussionsEnabled": true,
+      "nameWithOwner": "org/repo",
+      "defaultBranchRef": {
+        "name": "main"
+      },
+      "discussions": {
+        "totalCount": 2
+      },
+      "releases": {
+        "totalCount": 0
+      }
+    }
+  }
+}`
+
+var discussionsResponse = `{
+  "data": {
+    "repository": {
+      "discussions": {
+        "pageInfo": {
+          "hasNextPage": false,
+          "endCursor": "Y3Vyc29yOjI="
+        },
+        "edges": [
+          {
+            "node": {
+              "number": 5,
+              "title": "RFC: drop the v1 API",
+              "body": "Let's drop it.\n",
+              "url": "https://github.com/org/repo/discussions/5",
+              "isAnswered": true,
+              "createdAt": "2023-05-01T10:00:00Z",
+              "updatedAt": "2023-05-04T10:00:00Z",
+              "author": {
+                "login": "octocat"
+              },
+              "category": {
+                "slug": "rfcs"
+              },
+              "comments": {
+                "pageInfo": {
+                  "hasNextPage": true,
+                  "endCursor": "Y29tbWVudDox"
+                },
+                "edges": [
+                  {
+                    "node": {
+                      "id": "DC_1",
+                      "author": {
+                        "login": "hubot"
+                      },
+                      "body": "Who still uses it?",
+                      "createdAt": "2023-05-02T10:00:00Z",
+                      "isAnswer": false,
+                      "replies": {
+                        "pageInfo": {
+                          "hasNextPage": true,
+                          "endCursor": "cmVwbHk6MQ=="
+                        },
+                        "edges": [
+                          {
+                            "node": {
+                              "author": {
+                                "login": "octocat"
+                              },
+                              "body": "Nobody.",
+                              "createdAt": "2023-05-02T11:00:00Z"
+                            }
+                          }
+                        ]
+                      }
+                    }
+                  }
+                ]
+              }
+            }
+          },
+          {
+            "node": {
+              "number": 4,
+              "title": "How do I configure it?",
+              "body": "",
+              "url": "https://github.com/org/repo/discussions/4",
+              "isAnswered": false,
+              "createdAt": "2023-04-01T10:00:00Z",
+              "updatedAt": "2023-04-01T10:00:00Z",
+              "author": {
+                "login": "monalisa"
+              },
+              "category": {
+                "slug": "q-a"
+              },
+              "comments": {
+                "pageInfo": {
+                  "hasNextPage": false
+                },
+                "edges": []
+              }
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+var discussionRepliesResponse = `{
+  "data": {
+    "node": {
+      "replies": {
+        "pageInfo": {
+          "hasNextPage": false,
+          "endCursor": "cmVwbHk6Mg=="
+        },
+        "edges": [
+          {
+            "node": {
+              "author": {
+                "login": "hubot"
+              },
+              "body": "Then let's do it.",
+              "createdAt": "2023-05-02T12:00:00Z"
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+var discussionCommentsResponse = `{
+  "data": {
+    "repository": {
+      "discussion": {
+        "comments": {
+          "pageInfo": {
+            "hasNextPage": false,
+            "endCursor": "Y29tbWVudDoy"
+          },
+          "edges": [
+            {
+              "node": {
+                "id": "DC_2",
+                "author": {
+                  "login": "monalisa"
+                },
+                "body": "Dropped in v2.",
+                "createdAt": "2023-05-03T10:00:00Z",
+                "isAnswer": true,
+                "replies": {
+                  "pageInfo": {
+                    "hasNextPage": false
+                  },
+                  "edges": []
+                }
+              }
+            }
+          ]
+        }
+      }
+    }
+  }
+}`
+
+var discussion5Markdown = `---
+number: 5
+title: "RFC: drop the v1 API"
+category: "rfcs"
+author: "octocat"
+answered: true
+url: "https://github.com/org/repo/discussions/5"
+createdAt: 2023-05-01T10:00:00Z
+updatedAt: 2023-05-04T10:00:00Z
+---
+
+Let's drop it.
+
+## Comments
+
+### @hubot on 2023-05-02T10:00:00Z
+
+Who still uses it?
+
+#### @octocat on 2023-05-02T11:00:00Z
+
+Nobody.
+
+#### @hubot on 2023-05-02T12:00:00Z
+
+Then let's do it.
+
+### @monalisa on 2023-05-03T10:00:00Z (answer)
+
+Dropped in v2.
+`
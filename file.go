@@ -4,8 +4,7 @@
 package githubfs
 
 import (
-	"fmt"
-	"io"
+	"context"
 	"io/fs"
 	"sync"
 	"time"
@@ -20,7 +19,15 @@ type file struct {
 	repo    string
 	info    fileInfo
 	url     string
+	accept  string
 	content []byte
+
+	// The size is only known once the file is downloaded.
+	unknownSize bool
+
+	// fetchFn makes the contents of a file that isn't downloaded from a url
+	// when it is first opened.
+	fetchFn func(*FS, *file) ([]byte, error)
 }
 
 type fileOpt func(f *file)
@@ -38,6 +45,25 @@ func withUrl(url string) fileOpt {
 	}
 }
 
+func withAccept(mediaType string) fileOpt {
+	return func(f *file) {
+		f.accept = mediaType
+	}
+}
+
+func withUnknownSize() fileOpt {
+	return func(f *file) {
+		f.unknownSize = true
+	}
+}
+
+func withFileFetcher(fn func(*FS, *file) ([]byte, error)) fileOpt {
+	return func(f *file) {
+		f.fetchFn = fn
+		f.unknownSize = true
+	}
+}
+
 func withModTime(t time.Time) fileOpt {
 	return func(f *file) {
 		f.info.modTime = t
@@ -50,6 +76,12 @@ func withMode(mode fs.FileMode) fileOpt {
 	}
 }
 
+func withSys(sys any) fileOpt {
+	return func(f *file) {
+		f.info.sys = sys
+	}
+}
+
 func withSize(size int) fileOpt {
 	return func(f *file) {
 		if int64(len(f.content)) == 0 {
@@ -81,22 +113,21 @@ func (f *file) newFileHandle() (*fileHandle, error) {
 	f.m.Lock()
 	defer f.m.Unlock()
 
-	if int64(len(f.content)) != f.info.size {
-		resp, err := f.gfs.httpClient.Get(f.url)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != 200 {
-			return nil, fmt.Errorf("http status code not 200: %d\n", resp.StatusCode)
+	if f.unknownSize || int64(len(f.content)) != f.info.size {
+		var bod []byte
+		var err error
+		if f.fetchFn != nil {
+			bod, err = f.fetchFn(f.gfs, f)
+		} else {
+			bod, err = download(context.Background(), f.gfs, f.url, f.accept)
 		}
-		bod, err := io.ReadAll(resp.Body)
 		if err != nil {
 			return nil, err
 		}
 		f.content = bod
 		f.info.size = int64(len(bod))
+		f.unknownSize = false
+		f.fetchFn = nil
 	}
 
 	return newFileHandle(f.info, f.content), nil
@@ -123,6 +123,7 @@ func TestNewFileHandle(t *testing.T) {
 		payload     string
 		statusCode  int
 		expectErr   bool
+		expectAcc   string
 	}{
 		{
 			description: "simple test",
@@ -137,6 +138,17 @@ func TestNewFileHandle(t *testing.T) {
 		}, {
 			description: "simple empty test",
 			name:        "file_1",
+		}, {
+			description: "unknown size test",
+			name:        "file_1",
+			opts:        []fileOpt{withSize(0), withUnknownSize()},
+			payload:     "file_1 payload",
+		}, {
+			description: "media type test",
+			name:        "file_1",
+			opts:        []fileOpt{withAccept("application/vnd.github.diff")},
+			payload:     "file_1 payload",
+			expectAcc:   "application/vnd.github.diff",
 		},
 	}
 
@@ -146,6 +158,9 @@ func TestNewFileHandle(t *testing.T) {
 			require := require.New(t)
 
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if len(tc.expectAcc) > 0 {
+					assert.Equal(tc.expectAcc, r.Header.Get("Accept"))
+				}
 				if tc.statusCode != 0 {
 					w.WriteHeader(tc.statusCode)
 				}
@@ -162,7 +177,8 @@ func TestNewFileHandle(t *testing.T) {
 				repo: "repo",
 			}
 
-			f := newFile(&parent, tc.name, withUrl(server.URL), withSize(10))
+			opts := append([]fileOpt{withUrl(server.URL), withSize(10)}, tc.opts...)
+			f := newFile(&parent, tc.name, opts...)
 			require.NotNil(f)
 
 			got, err := f.newFileHandle()
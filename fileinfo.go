@@ -17,6 +17,7 @@ type fileInfo struct {
 	size    int64
 	modTime time.Time
 	mode    fs.FileMode
+	sys     any
 }
 
 // Name returns the base name of the file.
@@ -44,7 +45,9 @@ func (fi *fileInfo) IsDir() bool {
 	return fi.mode&fs.ModeDir > 0
 }
 
-// Sys returns the underlying data source (can return nil).  (Always nil).
+// Sys returns the underlying data source (can return nil).  The directories
+// of releases and the files in them return a *ReleaseInfo, everything else
+// returns nil.
 func (fi *fileInfo) Sys() any {
-	return nil
+	return fi.sys
 }
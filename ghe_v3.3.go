@@ -21,7 +21,7 @@ func getGitDirV3_3(gfs *FS, d *dir) error {
 	vars := map[string]any{
 		"owner": d.org,
 		"repo":  d.repo,
-		"exp":   d.branch + ":" + path,
+		"exp":   d.rev() + ":" + path,
 	}
 
 	/*
@@ -56,7 +56,7 @@ func getGitDirV3_3(gfs *FS, d *dir) error {
 	}
 
 	for _, entry := range query.Repository.Object.Tree.Entries {
-		url := strings.Join([]string{gfs.rawUrl, d.org, d.repo, d.branch, path, entry.Name}, "/")
+		url := d.rawUrl(entry.Name)
 
 		switch entry.Mode {
 		case ghModeFile:
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	dirNameGists     = "gists"
+	fileNameGistInfo = "gist.json"
+
+	// gistFileLimit is the most files of a gist that are listed.  The files
+	// of a gist can't be paged through in the GraphQL API.
+	gistFileLimit = 100
+)
+
+// gistInfo is the metadata about a gist written to gist.json.
+type gistInfo struct {
+	Id          string    `json:"id"`
+	Description string    `json:"description"`
+	Public      bool      `json:"public"`
+	Url         string    `json:"url"`
+	Files       []string  `json:"files"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// WithGists includes the gists owned by the user as <user>/gists/<gist-id>/.
+// Secret gists are only included for the authenticated user.  A repository
+// named "gists" owned by the same user hides the gists.  Only the first 100
+// files of a gist are included.
+func WithGists(user string) Option {
+	return func(gfs *FS) {
+		gfs.gists = append(gfs.gists, user)
+	}
+}
+
+// newGists creates the gists directory for a user if it isn't present already.
+func (gfs *FS) newGists(user string) {
+	u := gfs.root.mkdir(user, withOrg(user), notInPath())
+	if _, found := u.children[dirNameGists]; found {
+		return
+	}
+	u.mkdir(dirNameGists, withFetcher(getGistsDir), notInPath())
+}
+
+// getGistsDir fetches the gists of a user and makes each into a directory with
+// the gist files and the gist metadata.  A gist file named gist.json is kept
+// in place of the metadata.
+func getGistsDir(gfs *FS, d *dir) error {
+	vars := map[string]any{
+		"owner": d.org,
+		"count": 100,
+		"files": gistFileLimit,
+		"after": (*string)(nil),
+	}
+
+	/*
+		query {
+		  user(login: "user") {
+		    gists(first: 100, privacy: ALL) {
+		      edges {
+		        node {
+		          name
+		          description
+		          isPublic
+		          url
+		          createdAt
+		          updatedAt
+		          files(limit: 100) {
+		            name
+		            size
+		          }
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	more := true
+	for more {
+		var query struct {
+			User struct {
+				Gists struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+					Edges []struct {
+						Node struct {
+							Name        string
+							Description string
+							IsPublic    bool
+							Url         string
+							CreatedAt   time.Time
+							UpdatedAt   time.Time
+							Files       []struct {
+								Name string
+								Size int
+							} `graphql:"files(limit: $files)"`
+						}
+					}
+				} `graphql:"gists(first: $count, after: $after, privacy: ALL, orderBy: {field: CREATED_AT, direction: DESC})"`
+			} `graphql:"user(login: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return err
+		}
+
+		for _, edge := range query.User.Gists.Edges {
+			gist := edge.Node
+			info := gistInfo{
+				Id:          gist.Name,
+				Description: gist.Description,
+				Public:      gist.IsPublic,
+				Url:         gist.Url,
+				Files:       []string{},
+				CreatedAt:   gist.CreatedAt,
+				UpdatedAt:   gist.UpdatedAt,
+			}
+
+			gistDir := d.newDir(gist.Name, withDirModTime(gist.UpdatedAt))
+			for _, file := range gist.Files {
+				info.Files = append(info.Files, file.Name)
+
+				raw := strings.Join([]string{gfs.gistUrl, d.org, gist.Name, "raw", url.PathEscape(file.Name)}, "/")
+				gistDir.addFile(file.Name,
+					withUrl(raw),
+					withSize(file.Size),
+					withModTime(gist.UpdatedAt))
+			}
+
+			if _, found := gistDir.children[fileNameGistInfo]; found {
+				continue
+			}
+
+			buf, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return err
+			}
+			gistDir.addFile(fileNameGistInfo, withContent(buf), withModTime(gist.UpdatedAt))
+		}
+
+		more = query.User.Gists.PageInfo.HasNextPage
+		vars["after"] = query.User.Gists.PageInfo.EndCursor
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"testing"
+)
+
+func TestGists(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "fetch the gists",
+			opts:        []Option{WithGists("user")},
+			payload:     []string{gistsResponse001, gistsResponse002},
+			expect: []string{
+				"user/gists/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				"user/gists/bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+			},
+			unexpected: []string{"user/gists/cccccccccccccccccccccccccccccccc"},
+			contents: map[string]string{
+				"user/gists/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa/gist.json": gistAJson,
+			},
+			bodies: []string{`"owner":"user"`, `privacy: ALL`, `"files":100`},
+		}, {
+			description: "read a gist file",
+			opts:        []Option{WithGists("user")},
+			payload:     []string{gistsResponse002, gistNotesResponse},
+			contents: map[string]string{
+				"user/gists/bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb/notes file.md": gistNotesResponse,
+			},
+			requests: []string{
+				"POST /",
+				"GET /user/bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb/raw/notes%20file.md",
+			},
+		}, {
+			description: "a gist file named gist.json is kept in place of the metadata",
+			opts:        []Option{WithGists("user")},
+			payload:     []string{gistWithInfoFileResponse, gistInfoFileResponse},
+			contents: map[string]string{
+				"user/gists/dddddddddddddddddddddddddddddddd/gist.json": gistInfoFileResponse,
+			},
+			requests: []string{
+				"POST /",
+				"GET /user/dddddddddddddddddddddddddddddddd/raw/gist.json",
+			},
+		}, {
+			description: "fetch the gists, but there was a json error",
+			opts:        []Option{WithGists("user")},
+			payload:     []string{invalidJsonResponse},
+			expect:      []string{"user/gists/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+			expectErr:   true,
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+var gistsResponse001 = `{
+  "data": {
+    "user": {
+      "gists": {
+        "pageInfo": {
+          "hasNextPage": true,
+          "endCursor": "Y3Vyc29yOjE="
+        },
+        "edges": [
+          {
+            "node": {
+              "name": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+              "description": "Hello world",
+              "isPublic": true,
+              "url": "https://gist.github.com/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+              "createdAt": "2023-05-01T10:00:00Z",
+              "updatedAt": "2023-05-02T10:00:00Z",
+              "files": [
+                { "name": "hello.go", "size": 74 },
+                { "name": "go.mod", "size": 22 }
+              ]
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+var gistsResponse002 = `{
+  "data": {
+    "user": {
+      "gists": {
+        "pageInfo": {
+          "hasNextPage": false,
+          "endCursor": "Y3Vyc29yOjI="
+        },
+        "edges": [
+          {
+            "node": {
+              "name": "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+              "description": "",
+              "isPublic": false,
+              "url": "https://gist.github.com/bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+              "createdAt": "2023-04-01T10:00:00Z",
+              "updatedAt": "2023-04-01T10:00:00Z",
+              "files": [
+                { "name": "notes file.md", "size": 13 }
+              ]
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+var gistNotesResponse = "# Some notes\n"
+
+var gistWithInfoFileResponse = `{
+  "data": {
+    "user": {
+      "gists": {
+        "pageInfo": {
+          "hasNextPage": false,
+          "endCursor": "Y3Vyc29yOjE="
+        },
+        "edges": [
+          {
+            "node": {
+              "name": "dddddddddddddddddddddddddddddddd",
+              "description": "A json file",
+              "isPublic": true,
+              "url": "https://gist.github.com/dddddddddddddddddddddddddddddddd",
+              "createdAt": "2023-04-01T10:00:00Z",
+              "updatedAt": "2023-04-01T10:00:00Z",
+              "files": [
+                { "name": "gist.json", "size": 16 }
+              ]
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+var gistInfoFileResponse = `{"real": "file"}`
+
+var gistAJson = `{
+  "id": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+  "description": "Hello world",
+  "public": true,
+  "url": "https://gist.github.com/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+  "files": [
+    "hello.go",
+    "go.mod"
+  ],
+  "createdAt": "2023-05-01T10:00:00Z",
+  "updatedAt": "2023-05-02T10:00:00Z"
+}`
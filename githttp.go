@@ -0,0 +1,484 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The git smart http protocol is only used for the repositories that the
+// GraphQL API can't reach, like wikis.  Only as much of the protocol as a
+// shallow fetch of a single commit needs is implemented: there is no
+// side-band or multi-ack negotiation, and the whole pack is read into memory.
+const (
+	gitUploadPack        = "git-upload-pack"
+	gitUploadPackRequest = "application/x-git-upload-pack-request"
+	gitUserAgent         = "git/2.0 (githubfs)"
+	gitZeroSha           = "0000000000000000000000000000000000000000"
+)
+
+// The types of the objects in a pack.
+const (
+	gitObjCommit   = 1
+	gitObjTree     = 2
+	gitObjBlob     = 3
+	gitObjTag      = 4
+	gitObjOfsDelta = 6
+	gitObjRefDelta = 7
+)
+
+// gitObject is an object read from a pack.
+type gitObject struct {
+	kind int
+	data []byte
+}
+
+// gitHttp sends a request to the git smart http service of a repo and returns
+// the body.  Repos that don't exist or can't be read are reported as
+// fs.ErrNotExist since github doesn't tell them apart.
+func gitHttp(ctx context.Context, gfs *FS, method, url, contentType string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", gitUserAgent)
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := gfs.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("http status code not 200: %d %w", resp.StatusCode, fs.ErrNotExist)
+	default:
+		return nil, fmt.Errorf("http status code not 200: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// gitHead finds the branch HEAD points at in the repo at the url and the sha
+// of the branch.  An empty repo has no branch.
+func gitHead(ctx context.Context, gfs *FS, repoUrl string) (string, string, error) {
+	buf, err := gitHttp(ctx, gfs, http.MethodGet, repoUrl+"/info/refs?service="+gitUploadPack, "", nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	line, buf, _, err := pktLine(buf)
+	if err != nil {
+		return "", "", err
+	}
+	if string(line) != "# service="+gitUploadPack+"\n" {
+		return "", "", fmt.Errorf("not a git smart http response")
+	}
+
+	// The announcement of the service ends with a flush, then the refs end
+	// with another.
+	if _, buf, _, err = pktLine(buf); err != nil {
+		return "", "", err
+	}
+
+	var branch string
+	refs := make(map[string]string)
+	for {
+		var flush bool
+		line, buf, flush, err = pktLine(buf)
+		if err != nil {
+			return "", "", err
+		}
+		if flush {
+			break
+		}
+
+		// The capabilities follow the first ref, including where HEAD
+		// points.
+		ref, caps, _ := strings.Cut(strings.TrimSuffix(string(line), "\n"), "\x00")
+		for _, c := range strings.Fields(caps) {
+			if strings.HasPrefix(c, "symref=HEAD:"+refPrefixBranches) {
+				branch = strings.TrimPrefix(c, "symref=HEAD:"+refPrefixBranches)
+			}
+		}
+		sha, name, _ := strings.Cut(ref, " ")
+		if sha != gitZeroSha {
+			refs[name] = sha
+		}
+	}
+
+	if len(branch) == 0 {
+		return "", "", nil
+	}
+
+	return branch, refs[refPrefixBranches+branch], nil
+}
+
+// gitFetch fetches the commit and the objects it needs with a shallow fetch
+// and returns the objects by sha.
+func gitFetch(ctx context.Context, gfs *FS, repoUrl, sha string) (map[string]gitObject, error) {
+	var req bytes.Buffer
+	writePktLine(&req, "want "+sha+" ofs-delta\n")
+	writePktLine(&req, "deepen 1\n")
+	req.WriteString("0000")
+	writePktLine(&req, "done\n")
+
+	buf, err := gitHttp(ctx, gfs, http.MethodPost, repoUrl+"/"+gitUploadPack, gitUploadPackRequest, req.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	// The shallow commits and acknowledgement come before the pack.
+	for !bytes.HasPrefix(buf, []byte("PACK")) {
+		var line []byte
+		line, buf, _, err = pktLine(buf)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(line, []byte("ERR ")) {
+			return nil, fmt.Errorf("git error: %s", strings.TrimSpace(string(line[4:])))
+		}
+	}
+
+	return readPack(buf)
+}
+
+// pktLine splits the first pkt-line off the buffer.  A flush packet has no
+// data.
+func pktLine(buf []byte) ([]byte, []byte, bool, error) {
+	if len(buf) < 4 {
+		return nil, nil, false, io.ErrUnexpectedEOF
+	}
+	n, err := strconv.ParseUint(string(buf[:4]), 16, 16)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("invalid pkt-line length: %w", err)
+	}
+	if n == 0 {
+		return nil, buf[4:], true, nil
+	}
+	if n < 4 || int(n) > len(buf) {
+		return nil, nil, false, io.ErrUnexpectedEOF
+	}
+	return buf[4:n], buf[n:], false, nil
+}
+
+// writePktLine writes the line as a pkt-line.
+func writePktLine(b *bytes.Buffer, line string) {
+	fmt.Fprintf(b, "%04x%s", len(line)+4, line)
+}
+
+// packEntry is an object in a pack that may still need its delta applied.
+type packEntry struct {
+	obj     gitObject
+	baseOfs int64
+	baseSha string
+}
+
+// readPack reads every object in the pack and applies the deltas, returning
+// the objects by sha.
+func readPack(data []byte) (map[string]gitObject, error) {
+	if len(data) < 12 || string(data[:4]) != "PACK" {
+		return nil, fmt.Errorf("not a git pack")
+	}
+	count := int(data[8])<<24 | int(data[9])<<16 | int(data[10])<<8 | int(data[11])
+
+	r := bytes.NewReader(data[12:])
+	offset := func() int64 {
+		return int64(len(data)) - int64(r.Len())
+	}
+
+	// The count is only a hint for the allocations since every object takes
+	// at least a byte of the pack.
+	hint := count
+	if hint > len(data) {
+		hint = len(data)
+	}
+
+	entries := make(map[int64]*packEntry, hint)
+	order := make([]int64, 0, hint)
+	for i := 0; i < count; i++ {
+		start := offset()
+
+		c, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		e := packEntry{obj: gitObject{kind: int(c>>4) & 7}}
+		for c&0x80 != 0 {
+			if c, err = r.ReadByte(); err != nil {
+				return nil, err
+			}
+		}
+
+		switch e.obj.kind {
+		case gitObjOfsDelta:
+			c, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			back := int64(c & 0x7f)
+			for c&0x80 != 0 {
+				if c, err = r.ReadByte(); err != nil {
+					return nil, err
+				}
+				back = (back+1)<<7 | int64(c&0x7f)
+			}
+			e.baseOfs = start - back
+		case gitObjRefDelta:
+			sha := make([]byte, 20)
+			if _, err := io.ReadFull(r, sha); err != nil {
+				return nil, err
+			}
+			e.baseSha = hex.EncodeToString(sha)
+		}
+
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		if e.obj.data, err = io.ReadAll(zr); err != nil {
+			return nil, err
+		}
+		zr.Close()
+
+		entries[start] = &e
+		order = append(order, start)
+	}
+
+	// Deltas may be based on other deltas, so keep resolving until all of
+	// them are done.
+	objects := make(map[string]gitObject, len(entries))
+	for len(order) > 0 {
+		var left []int64
+		for _, ofs := range order {
+			e := entries[ofs]
+			switch e.obj.kind {
+			case gitObjOfsDelta, gitObjRefDelta:
+				var base gitObject
+				var found bool
+				if e.obj.kind == gitObjOfsDelta {
+					if b, ok := entries[e.baseOfs]; ok && b.obj.kind < gitObjOfsDelta {
+						base, found = b.obj, true
+					}
+				} else {
+					base, found = objects[e.baseSha]
+				}
+				if !found {
+					left = append(left, ofs)
+					continue
+				}
+				data, err := applyDelta(base.data, e.obj.data)
+				if err != nil {
+					return nil, err
+				}
+				e.obj = gitObject{kind: base.kind, data: data}
+			}
+			objects[gitObjectSha(e.obj)] = e.obj
+		}
+		if len(left) == len(order) {
+			return nil, fmt.Errorf("git pack has deltas without a base")
+		}
+		order = left
+	}
+
+	return objects, nil
+}
+
+// applyDelta makes the object from the delta and the base object.
+func applyDelta(base, delta []byte) ([]byte, error) {
+	errInvalid := errors.New("invalid git delta")
+
+	varint := func() (int, bool) {
+		var n, shift int
+		for len(delta) > 0 {
+			c := delta[0]
+			delta = delta[1:]
+			n |= int(c&0x7f) << shift
+			shift += 7
+			if c&0x80 == 0 {
+				return n, true
+			}
+		}
+		return 0, false
+	}
+
+	srcSize, ok := varint()
+	if !ok || srcSize != len(base) {
+		return nil, errInvalid
+	}
+	dstSize, ok := varint()
+	if !ok {
+		return nil, errInvalid
+	}
+
+	// The size is only a hint for the allocation until the delta is applied.
+	hint := dstSize
+	if hint > len(delta) {
+		hint = len(delta)
+	}
+
+	out := make([]byte, 0, hint)
+	for len(delta) > 0 {
+		op := delta[0]
+		delta = delta[1:]
+
+		switch {
+		case op&0x80 != 0:
+			// Copy from the base with the offset and size in the bytes
+			// selected by the bits of the op.
+			var ofs, size int
+			for i := 0; i < 7; i++ {
+				if op&(1<<i) == 0 {
+					continue
+				}
+				if len(delta) == 0 {
+					return nil, errInvalid
+				}
+				if i < 4 {
+					ofs |= int(delta[0]) << (8 * i)
+				} else {
+					size |= int(delta[0]) << (8 * (i - 4))
+				}
+				delta = delta[1:]
+			}
+			if size == 0 {
+				size = 0x10000
+			}
+			if ofs+size > len(base) {
+				return nil, errInvalid
+			}
+			out = append(out, base[ofs:ofs+size]...)
+		case op != 0:
+			// Insert the next bytes of the delta.
+			if int(op) > len(delta) {
+				return nil, errInvalid
+			}
+			out = append(out, delta[:op]...)
+			delta = delta[op:]
+		default:
+			return nil, errInvalid
+		}
+	}
+
+	if len(out) != dstSize {
+		return nil, errInvalid
+	}
+
+	return out, nil
+}
+
+// gitObjectSha returns the sha git names the object by.
+func gitObjectSha(obj gitObject) string {
+	var kind string
+	switch obj.kind {
+	case gitObjCommit:
+		kind = "commit"
+	case gitObjTree:
+		kind = "tree"
+	case gitObjBlob:
+		kind = "blob"
+	case gitObjTag:
+		kind = "tag"
+	}
+
+	h := sha1.New()
+	fmt.Fprintf(h, "%s %d\x00", kind, len(obj.data))
+	h.Write(obj.data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// gitCommitTree returns the sha of the tree of the commit and when it was
+// committed.
+func gitCommitTree(objects map[string]gitObject, sha string) (string, time.Time, error) {
+	commit, found := objects[sha]
+	if !found || commit.kind != gitObjCommit {
+		return "", time.Time{}, fmt.Errorf("commit %s not found %w", sha, fs.ErrNotExist)
+	}
+
+	var tree string
+	var when time.Time
+	for _, line := range strings.Split(string(commit.data), "\n") {
+		if len(line) == 0 {
+			break
+		}
+		switch key, value, _ := strings.Cut(line, " "); key {
+		case "tree":
+			tree = value
+		case "committer":
+			// The time is the second to last field, before the time zone.
+			fields := strings.Fields(value)
+			if len(fields) >= 2 {
+				if sec, err := strconv.ParseInt(fields[len(fields)-2], 10, 64); err == nil {
+					when = time.Unix(sec, 0).UTC()
+				}
+			}
+		}
+	}
+
+	return tree, when, nil
+}
+
+// gitTreeToDir fills in the directory with the files of the tree.
+func gitTreeToDir(objects map[string]gitObject, sha string, d *dir, modTime time.Time) error {
+	tree, found := objects[sha]
+	if !found || tree.kind != gitObjTree {
+		return fmt.Errorf("tree %s not found %w", sha, fs.ErrNotExist)
+	}
+
+	data := tree.data
+	for len(data) > 0 {
+		sp := bytes.IndexByte(data, ' ')
+		nul := bytes.IndexByte(data, 0)
+		if sp < 0 || nul < sp || nul+21 > len(data) {
+			return fmt.Errorf("invalid git tree %s", sha)
+		}
+		mode, err := strconv.ParseInt(string(data[:sp]), 8, 32)
+		if err != nil {
+			return err
+		}
+		name := string(data[sp+1 : nul])
+		child := hex.EncodeToString(data[nul+1 : nul+21])
+		data = data[nul+21:]
+
+		switch mode {
+		case ghModeFile, ghModeExecutable:
+			blob, found := objects[child]
+			if !found {
+				return fmt.Errorf("blob %s not found %w", child, fs.ErrNotExist)
+			}
+			opts := []fileOpt{withContent(blob.data), withModTime(modTime)}
+			if mode == ghModeExecutable {
+				opts = append(opts, withMode(fs.FileMode(0755)))
+			}
+			d.addFile(name, opts...)
+		case ghModeDirectory:
+			sub := d.newDir(name, withDirModTime(modTime))
+			if err := gitTreeToDir(objects, child, sub, modTime); err != nil {
+				return err
+			}
+		case ghModeSubmodule: // TODO
+		case ghModeSymlink: // TODO
+		default:
+			return fmt.Errorf("unknown file mode")
+		}
+	}
+
+	return nil
+}
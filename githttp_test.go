@@ -0,0 +1,239 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPackObject encodes an object for a pack.  The prefix holds the base of
+// a delta.
+func testPackObject(kind int, data []byte, prefix ...byte) []byte {
+	var b bytes.Buffer
+
+	size := len(data)
+	c := byte(kind<<4) | byte(size&0x0f)
+	size >>= 4
+	for size > 0 {
+		b.WriteByte(c | 0x80)
+		c = byte(size & 0x7f)
+		size >>= 7
+	}
+	b.WriteByte(c)
+	b.Write(prefix)
+
+	zw := zlib.NewWriter(&b)
+	_, _ = zw.Write(data)
+	_ = zw.Close()
+
+	return b.Bytes()
+}
+
+// testPack makes a pack of the encoded objects.
+func testPack(objects ...[]byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("PACK")
+	b.Write([]byte{0, 0, 0, 2})
+	n := len(objects)
+	b.Write([]byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)})
+	for _, obj := range objects {
+		b.Write(obj)
+	}
+	// The checksum isn't checked.
+	b.Write(make([]byte, 20))
+	return b.Bytes()
+}
+
+// testTree makes a tree object of the entries in "mode name" to sha pairs.
+func testTree(entries ...string) []byte {
+	var b bytes.Buffer
+	for i := 0; i+1 < len(entries); i += 2 {
+		sha, _ := hex.DecodeString(entries[i+1])
+		b.WriteString(entries[i])
+		b.WriteByte(0)
+		b.Write(sha)
+	}
+	return b.Bytes()
+}
+
+// testRefs makes the ref advertisement of a repo with HEAD pointing at the
+// branch.
+func testRefs(branch, sha string) string {
+	var b bytes.Buffer
+	writePktLine(&b, "# service=git-upload-pack\n")
+	b.WriteString("0000")
+	if len(branch) == 0 {
+		writePktLine(&b, gitZeroSha+" capabilities^{}\x00ofs-delta shallow\n")
+	} else {
+		writePktLine(&b, sha+" HEAD\x00ofs-delta shallow symref=HEAD:refs/heads/"+branch+"\n")
+		writePktLine(&b, sha+" refs/heads/"+branch+"\n")
+	}
+	b.WriteString("0000")
+	return b.String()
+}
+
+// testUploadPack makes the reply to a shallow fetch of the commit.
+func testUploadPack(sha string, pack []byte) string {
+	var b bytes.Buffer
+	writePktLine(&b, "shallow "+sha+"\n")
+	b.WriteString("0000")
+	writePktLine(&b, "NAK\n")
+	b.Write(pack)
+	return b.String()
+}
+
+func TestReadPack(t *testing.T) {
+	base := []byte("hello, world\n")
+	baseSha := gitObjectSha(gitObject{kind: gitObjBlob, data: base})
+
+	// Copy "hello, " from the base then insert "wiki\n".
+	delta := []byte{byte(len(base)), 12, 0x90, 7, 5, 'w', 'i', 'k', 'i', '\n'}
+	want := "hello, wiki\n"
+	wantSha := gitObjectSha(gitObject{kind: gitObjBlob, data: []byte(want)})
+
+	baseObj := testPackObject(gitObjBlob, base)
+	refBase, _ := hex.DecodeString(baseSha)
+
+	// A pack that claims to hold far more objects than it does.
+	tooMany := testPack(baseObj)
+	copy(tooMany[8:12], []byte{0xff, 0xff, 0xff, 0xff})
+
+	// A delta that claims to make a far larger object than it does.
+	tooLarge := []byte{byte(len(base)), 0xff, 0xff, 0xff, 0xff, 0x0f, 0x90, 7}
+
+	tests := []struct {
+		description string
+		pack        []byte
+		expectErr   bool
+	}{
+		{
+			description: "a delta based on an offset",
+			// The offset back to the base is the size of the base object.
+			pack: testPack(baseObj, testPackObject(gitObjOfsDelta, delta, byte(len(baseObj)))),
+		}, {
+			description: "a delta based on a sha",
+			pack:        testPack(testPackObject(gitObjRefDelta, delta, refBase...), baseObj),
+		}, {
+			description: "a delta without a base",
+			pack:        testPack(testPackObject(gitObjRefDelta, delta, make([]byte, 20)...)),
+			expectErr:   true,
+		}, {
+			description: "an invalid delta",
+			pack:        testPack(baseObj, testPackObject(gitObjOfsDelta, delta[:5], byte(len(baseObj)))),
+			expectErr:   true,
+		}, {
+			description: "a delta larger than it makes",
+			pack:        testPack(baseObj, testPackObject(gitObjOfsDelta, tooLarge, byte(len(baseObj)))),
+			expectErr:   true,
+		}, {
+			description: "a pack with fewer objects than it claims",
+			pack:        tooMany,
+			expectErr:   true,
+		}, {
+			description: "not a pack",
+			pack:        []byte("not a pack"),
+			expectErr:   true,
+		}, {
+			description: "a truncated pack",
+			pack:        testPack(baseObj)[:20],
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			objects, err := readPack(tc.pack)
+			if tc.expectErr {
+				assert.Error(err)
+				return
+			}
+
+			require.NoError(err)
+			require.Contains(objects, wantSha)
+			assert.Equal(want, string(objects[wantSha].data))
+			assert.Equal(gitObjBlob, objects[wantSha].kind)
+			assert.Contains(objects, baseSha)
+		})
+	}
+}
+
+func TestPktLine(t *testing.T) {
+	tests := []struct {
+		description string
+		in          string
+		line        string
+		rest        string
+		flush       bool
+		expectErr   bool
+	}{
+		{
+			description: "a line",
+			in:          "0009line\nrest",
+			line:        "line\n",
+			rest:        "rest",
+		}, {
+			description: "a flush",
+			in:          "0000rest",
+			flush:       true,
+			rest:        "rest",
+		}, {
+			description: "too short",
+			in:          "00",
+			expectErr:   true,
+		}, {
+			description: "an invalid length",
+			in:          "zzzz",
+			expectErr:   true,
+		}, {
+			description: "longer than the buffer",
+			in:          "0010line",
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			line, rest, flush, err := pktLine([]byte(tc.in))
+			if tc.expectErr {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tc.line, string(line))
+			assert.Equal(tc.rest, string(rest))
+			assert.Equal(tc.flush, flush)
+		})
+	}
+}
+
+func TestGitCommitTree(t *testing.T) {
+	assert := assert.New(t)
+
+	commit := gitObject{
+		kind: gitObjCommit,
+		data: []byte(fmt.Sprintf("tree %s\nauthor a <a@b> 1 +0000\ncommitter c <c@d> 1600000000 -0500\n\nmessage\n", gitZeroSha)),
+	}
+	sha := gitObjectSha(commit)
+
+	tree, when, err := gitCommitTree(map[string]gitObject{sha: commit}, sha)
+	assert.NoError(err)
+	assert.Equal(gitZeroSha, tree)
+	assert.Equal(int64(1600000000), when.Unix())
+
+	_, _, err = gitCommitTree(map[string]gitObject{}, sha)
+	assert.Error(err)
+}
@@ -14,10 +14,14 @@ package githubfs
 import (
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	gql "github.com/hasura/go-graphql-client"
 )
@@ -25,27 +29,92 @@ import (
 // General structure:
 // org/
 // └── repo
+//     ├── actions
+//     │   ├── runs
+//     │   │   └── id
+//     │   │       ├── artifacts
+//     │   │       ├── logs
+//     │   │       └── run.json
+//     │   └── workflows
+//     │       └── ci.yml
+//     │           └── latest-success
+//     ├── commits
+//     │   └── sha
+//     │       └── files
+//     ├── compare
+//     │   └── base...head.patch
+//     ├── discussions
+//     │   └── category
+//     │       └── 1.md
 //     ├── git
 //     │   └── branch
 //     │       └── files
+//     ├── history
+//     │   └── branch
+//     │       └── sha
+//     │           ├── commit.json
+//     │           └── diff.patch
+//     ├── issues
+//     │   └── 1.md
 //     ├── packages
 //     │   └── container
-//     └── releases
-//         └── v0.0.1
-//             ├── description.md
-//             ├── file-0.0.1.tar.gz
-//             └── sha256sum.txt
+//     │       └── name
+//     │           └── version
+//     │               ├── config.json
+//     │               ├── manifest.json
+//     │               └── metadata.json
+//     ├── pulls
+//     │   └── 1
+//     │       ├── base
+//     │       ├── diff.patch
+//     │       ├── files.txt
+//     │       ├── head
+//     │       └── pull.json
+//     ├── releases
+//     │   └── v0.0.1
+//     │       ├── description.md
+//     │       ├── file-0.0.1.tar.gz
+//     │       ├── release.json
+//     │       ├── sha256sum.txt
+//     │       ├── source.tar.gz
+//     │       └── source.zip
+//     ├── tags
+//     │   └── v0.0.1
+//     │       └── files
+//     └── wiki
+//         └── files
 //
 //  Depth:
 //  0   1    2   3
-//  org/repo/git/branch/...
-//          /packages/container/...
+//  org/repo/actions/runs/{id}/...
+//          /actions/workflows/{file}/latest-success/...
+//          /commits/{sha}/...
+//          /compare/{base}...{head}.patch
+//          /discussions/{category}/{number}.md
+//          /git/branch/...
+//          /history/branch/{sha}/...
+//          /issues/{number}.md
+//          /packages/{type}/{name}/{version}/...
+//          /pulls/{number}/head/...
 //          /releases/{tag}/files/...
+//          /releases/latest/files/...
+//          /releases/{draft|prerelease}/{tag}/files/...
+//          /tags/{tag}/...
+//          /wiki/...
 
 const (
-	dirNameGit      = "git"
-	dirNameReleases = "releases"
-	//dirNamePackages = "packages"	// Add when supported.
+	dirNameActions     = "actions"
+	dirNameCommits     = "commits"
+	dirNameCompare     = "compare"
+	dirNameDiscussions = "discussions"
+	dirNameGit         = "git"
+	dirNameHistory     = "history"
+	dirNameIssues      = "issues"
+	dirNamePackages    = "packages"
+	dirNamePulls       = "pulls"
+	dirNameReleases    = "releases"
+	dirNameTags        = "tags"
+	dirNameWiki        = "wiki"
 )
 
 const (
@@ -61,6 +130,8 @@ type input struct {
 	repo          string
 	branch        string
 	allowArchived bool
+	allBranches   bool
+	globs         []string
 }
 
 // ensure the FS matches the interface
@@ -68,15 +139,40 @@ var _ fs.FS = (*FS)(nil)
 
 // FS provides the githubfs
 type FS struct {
-	httpClient  *http.Client
-	gqlClient   *gql.Client
-	connected   bool
-	githubUrl   string
-	rawUrl      string
-	inputs      []input
-	threshold   int
-	root        *dir
-	getGitDirFn func(*FS, *dir) error
+	httpClient   *http.Client
+	regClient    *http.Client
+	gqlClient    *gql.Client
+	connected    bool
+	githubUrl    string
+	rawUrl       string
+	restUrl      string
+	gistUrl      string
+	gitUrl       string
+	containerUrl string
+	inputs       []input
+	gists        []string
+	pins         map[string]string
+	lock         map[string]string
+	asOf         time.Time
+	issueStates  []issueState
+	issueLabels  []string
+	historyDepth int
+	blameFiles   bool
+	releases     ReleaseFilter
+	constraint   versionConstraint
+	maxReleases  int
+	actions      bool
+	packages     bool
+	compares     map[string]*Comparison
+	compareKeys  []string
+	comparesLock sync.Mutex
+	packagesLock sync.Mutex
+	staged       staging
+	ownerPkgs    map[string][]restPackage
+	threshold    int
+	root         *dir
+	getGitDirFn  func(*FS, *dir) error
+	optionErr    error
 }
 
 // Option is the type used for options.
@@ -130,6 +226,24 @@ func WithRepo(org string, repo string, branches ...string) Option {
 	}
 }
 
+// WithBranches configures a specific owner and repository where the branches
+// are discovered lazily from github instead of being named up front.  Only the
+// branches matching one of the glob patterns are included.  The patterns use
+// the path.Match syntax, so "release/*" matches "release/1.0" but not
+// "release/1.0/rc".  If no patterns are specified all the branches are
+// included.
+func WithBranches(org string, repo string, globs ...string) Option {
+	return func(gfs *FS) {
+		gfs.inputs = append(gfs.inputs, input{
+			org:           org,
+			repo:          repo,
+			allowArchived: true,
+			allBranches:   len(globs) == 0,
+			globs:         globs,
+		})
+	}
+}
+
 // WithSlug provides a way to easily configure a set of repos, or unique repo
 // based on the slug string.
 //
@@ -207,6 +321,12 @@ func WithGithubEnterprise(baseURL, version string) Option {
 		return func(gfs *FS) {
 			gfs.githubUrl = baseURL + "/api/graphql"
 			gfs.rawUrl = baseURL + "/raw"
+			gfs.restUrl = baseURL + "/api/v3"
+			gfs.gistUrl = baseURL + "/gist"
+			gfs.gitUrl = baseURL
+			if u, err := url.Parse(baseURL); err == nil {
+				gfs.containerUrl = u.Scheme + "://containers." + u.Host
+			}
 			gfs.getGitDirFn = getGitDirV3_3
 		}
 	}
@@ -217,6 +337,10 @@ func withTestURL(url string) Option {
 	return func(gfs *FS) {
 		gfs.githubUrl = url
 		gfs.rawUrl = url
+		gfs.restUrl = url
+		gfs.gistUrl = url
+		gfs.gitUrl = url
+		gfs.containerUrl = url
 	}
 }
 
@@ -224,11 +348,21 @@ func withTestURL(url string) Option {
 func New(opts ...Option) *FS {
 	tenMB := 10 * 1024
 	gfs := FS{
-		httpClient:  http.DefaultClient,
-		githubUrl:   "https://api.github.com/graphql",
-		rawUrl:      "https://raw.githubusercontent.com",
-		threshold:   tenMB,
-		getGitDirFn: getGitDir,
+		httpClient:   http.DefaultClient,
+		regClient:    http.DefaultClient,
+		githubUrl:    "https://api.github.com/graphql",
+		rawUrl:       "https://raw.githubusercontent.com",
+		restUrl:      "https://api.github.com",
+		gistUrl:      "https://gist.githubusercontent.com",
+		gitUrl:       "https://github.com",
+		containerUrl: "https://ghcr.io",
+		threshold:    tenMB,
+		getGitDirFn:  getGitDir,
+		historyDepth: defaultHistoryDepth,
+		pins:         make(map[string]string),
+		lock:         make(map[string]string),
+		compares:     make(map[string]*Comparison),
+		ownerPkgs:    make(map[string][]restPackage),
 	}
 
 	for _, opt := range opts {
@@ -253,6 +387,22 @@ func (gfs *FS) Open(name string) (fs.File, error) {
 
 	child, err := gfs.get(name)
 	if err != nil {
+		// Files are also available at any revision as path@sha.
+		if p, sha, ok := splitRevision(name); ok && errors.Is(err, fs.ErrNotExist) {
+			f, rerr := gfs.openRevision(p, sha)
+			if rerr == nil {
+				return f, nil
+			}
+			err = rerr
+		}
+		// The blame of files is available as path.blame.json if enabled.
+		if p, ok := gfs.splitBlame(name); ok && errors.Is(err, fs.ErrNotExist) {
+			f, berr := gfs.openBlame(p)
+			if berr == nil {
+				return f, nil
+			}
+			err = berr
+		}
 		return nil, fmt.Errorf("open %s error fetching file: %w", name, err)
 	}
 
@@ -272,6 +422,9 @@ func (gfs *FS) connect() error {
 	if gfs.connected {
 		return nil
 	}
+	if gfs.optionErr != nil {
+		return gfs.optionErr
+	}
 
 	// Fetch the bulk things first, so specific repos with extra details
 	// can be added afterwards safely.
@@ -289,6 +442,9 @@ func (gfs *FS) connect() error {
 			}
 		}
 	}
+	for _, user := range gfs.gists {
+		gfs.newGists(user)
+	}
 	gfs.connected = true
 	return nil
 }
@@ -309,28 +465,113 @@ func (gfs *FS) get(path string) (any, error) {
 	return dir, nil
 }
 
+// repoInfo is the information about a repository used to build the repository
+// directory structure.
+type repoInfo struct {
+	Name          string
+	DiskUsage     int
+	IsArchived    bool
+	IsDisabled    bool
+	NameWithOwner string
+	Owner         struct {
+		Typename string `graphql:"__typename"`
+	}
+	HasIssuesEnabled      bool
+	HasWikiEnabled        bool
+	HasDiscussionsEnabled bool
+	DefaultBranchRef      struct {
+		Name   string
+		Target struct {
+			Oid string
+		}
+	}
+	Discussions struct {
+		TotalCount int
+	}
+	Issues struct {
+		TotalCount int
+	}
+	PullRequests struct {
+		TotalCount int
+	}
+	Releases struct {
+		TotalCount int
+	}
+	Tags struct {
+		TotalCount int
+	} `graphql:"tags: refs(refPrefix: \"refs/tags/\")"`
+}
+
 // newRepo creates a new repo structure if it isn't present already.  Each needed
-// node is created and linked.  The resulting nodes are returned by a map.
-func (gfs *FS) newRepo(org, repo, branch string, releases, packages bool, size int) {
+// node is created and linked.  The branch is pinned to the head commit unless
+// a lock or a point in time says otherwise.  The resulting repo node is
+// returned.
+func (gfs *FS) newRepo(org, repo, branch, head string, info *repoInfo) (*dir, error) {
+	size := info.DiskUsage
 	o := gfs.root.mkdir(org, withOrg(org), notInPath())
-	r := o.mkdir(repo, withRepo(repo), notInPath())
-	if releases {
+	r := o.mkdir(repo, withRepo(repo), withDiskUsage(size), notInPath())
+	if info.HasDiscussionsEnabled && info.Discussions.TotalCount > 0 {
+		r.mkdir(dirNameDiscussions, withFetcher(getDiscussionsDir), notInPath())
+	}
+	if info.HasIssuesEnabled && info.Issues.TotalCount > 0 {
+		r.mkdir(dirNameIssues, withFetcher(getIssuesDir), notInPath())
+	}
+	if info.PullRequests.TotalCount > 0 {
+		r.mkdir(dirNamePulls, withFetcher(getPullsDir), notInPath())
+	}
+	if info.Releases.TotalCount > 0 {
 		r.mkdir(dirNameReleases, withFetcher(getReleaseDir), notInPath())
 	}
-	//if packages {
-	//	// Add when we can get the data via graphql.
-	//	r.mkdir(dirNamePackages, withFetcher(nil))
-	//}
+	if info.Tags.TotalCount > 0 {
+		r.mkdir(dirNameTags, withFetcher(getTagsDir), notInPath())
+	}
+	if info.HasWikiEnabled {
+		r.mkdir(dirNameWiki, withRepo(repo+wikiSuffix), withFetcher(getWikiDir), notInPath())
+	}
 
-	git := r.mkdir(dirNameGit, notInPath())
+	if gfs.actions {
+		newActions(r)
+	}
+	if gfs.packages {
+		r.mkdir(dirNamePackages, withFetcher(packagesFetcher(info.Owner.Typename)), notInPath())
+	}
+	r.mkdir(dirNameCommits, withLookup(lookupCommit), notInPath())
+	r.mkdir(dirNameCompare, withLookup(lookupCompare), notInPath())
+	r.mkdir(dirNameHistory, withFetcher(getHistoryDir), notInPath())
+	git := r.mkdir(dirNameGit, withLookup(lookupAsOf), notInPath())
 
 	if len(branch) > 0 {
-		opt := withFetcher(gfs.getGitDirFn)
-		if size <= gfs.threshold {
-			opt = withFetcher(getEntireGitDir)
+		oid, err := gfs.pin(org, repo, branch, head)
+		if err != nil {
+			// The branch didn't exist at the point in time.
+			if errors.Is(err, fs.ErrNotExist) {
+				return r, nil
+			}
+			return nil, err
 		}
-		git.mkdir(branch, withBranch(branch), notInPath(), opt)
+		git.mkref(branch, withBranch(branch), withCommit(oid), notInPath(), gfs.treeFetcher(size))
 	}
+
+	return r, nil
+}
+
+// treeFetcher picks the fetcher used to populate a git tree based on the size
+// of the repository, and marks the directory as holding the tree.
+func (gfs *FS) treeFetcher(size int) dirOpt {
+	fn := gfs.gitDirFn(size)
+	return func(d *dir) {
+		d.fetchFn = fn
+		d.tree = true
+	}
+}
+
+// gitDirFn picks the function used to populate a git tree based on the size of
+// the repository.
+func (gfs *FS) gitDirFn(size int) func(*FS, *dir) error {
+	if size <= gfs.threshold {
+		return getEntireGitDir
+	}
+	return gfs.getGitDirFn
 }
 
 // fetchRepo calls github and asks for a single specific repo, and links it
@@ -341,38 +582,59 @@ func (gfs *FS) fetchRepo(s input) (err error) {
 		"repo":  s.repo,
 	}
 
-	var query struct {
-		Repo struct {
-			DiskUsage        int
-			IsArchived       bool
-			IsDisabled       bool
-			NameWithOwner    string
-			DefaultBranchRef struct {
-				Name string
-			}
-			Releases struct {
-				TotalCount int
-			}
-		} `graphql:"repository(name: $repo, owner: $owner)"`
-	}
+	// The head of the branch is only asked for if a branch is named, since
+	// the default branch comes with the repo.
+	var info repoInfo
+	var head string
+	if len(s.branch) == 0 {
+		var query struct {
+			Repo repoInfo `graphql:"repository(name: $repo, owner: $owner)"`
+		}
 
-	if err = gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
-		return err
+		if err = gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return err
+		}
+		info = query.Repo
+	} else {
+		vars["branch"] = refPrefixBranches + s.branch
+
+		var query struct {
+			Repo   repoInfo `graphql:"repository(name: $repo, owner: $owner)"`
+			Branch struct {
+				Ref struct {
+					Target struct {
+						Oid string
+					}
+				} `graphql:"ref(qualifiedName: $branch)"`
+			} `graphql:"branch: repository(name: $repo, owner: $owner)"`
+		}
+
+		if err = gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return err
+		}
+		info = query.Repo
+		head = query.Branch.Ref.Target.Oid
 	}
 
-	if !s.allowArchived && query.Repo.IsArchived ||
-		query.Repo.IsDisabled ||
-		query.Repo.NameWithOwner != s.org+"/"+s.repo {
+	if !s.allowArchived && info.IsArchived ||
+		info.IsDisabled ||
+		info.NameWithOwner != s.org+"/"+s.repo {
 		return nil
 	}
 
 	branch := s.branch
-	if len(branch) == 0 {
-		branch = query.Repo.DefaultBranchRef.Name
+	if len(branch) == 0 && !s.allBranches && len(s.globs) == 0 {
+		branch = info.DefaultBranchRef.Name
+		head = info.DefaultBranchRef.Target.Oid
+	}
+	r, err := gfs.newRepo(s.org, s.repo, branch, head, &info)
+	if err != nil {
+		return err
+	}
+
+	if s.allBranches || len(s.globs) > 0 {
+		r.mkdir(dirNameGit).addBranchGlobs(s.allBranches, s.globs)
 	}
-	releases := query.Repo.Releases.TotalCount > 0
-	size := query.Repo.DiskUsage
-	gfs.newRepo(s.org, s.repo, branch, releases, false, size)
 
 	return nil
 }
@@ -396,19 +658,7 @@ func (gfs *FS) fetchRepos(s input) (err error) {
 						EndCursor   string
 					}
 					Edges []struct {
-						Node struct {
-							Name             string
-							DiskUsage        int
-							IsArchived       bool
-							IsDisabled       bool
-							NameWithOwner    string
-							DefaultBranchRef struct {
-								Name string
-							}
-							Releases struct {
-								TotalCount int
-							}
-						}
+						Node repoInfo
 					}
 				} `graphql:"repositories(orderBy: {field: NAME, direction: ASC}, first: $count, after: $after)"`
 			} `graphql:"repositoryOwner(login: $owner)"`
@@ -426,9 +676,10 @@ func (gfs *FS) fetchRepos(s input) (err error) {
 			}
 
 			branch := edge.Node.DefaultBranchRef.Name
-			releases := edge.Node.Releases.TotalCount > 0
-			size := edge.Node.DiskUsage
-			gfs.newRepo(s.org, edge.Node.Name, branch, releases, false, size)
+			head := edge.Node.DefaultBranchRef.Target.Oid
+			if _, err = gfs.newRepo(s.org, edge.Node.Name, branch, head, &edge.Node); err != nil {
+				return err
+			}
 		}
 
 		more = query.Owner.Repo.PageInfo.HasNextPage
@@ -441,10 +692,52 @@ func (gfs *FS) fetchRepos(s input) (err error) {
 // getEntireGitDir fetches the entire directory as a tarball and decodes the
 // result into the filesystem subtree.  For small repos this is much faster.
 func getEntireGitDir(gfs *FS, d *dir) error {
+	url, err := tarballUrl(gfs, d)
+	if err != nil {
+		return err
+	}
+
+	resp, err := gfs.httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("http status code not 200: %d", resp.StatusCode)
+	}
+
+	ct := resp.Header.Get("Content-Type")
+	bodyReader := resp.Body
+	switch ct {
+	case "application/x-gzip", "application/gzip":
+		if !resp.Uncompressed {
+			zr, err := gzip.NewReader(bodyReader)
+			if err != nil {
+				return err
+			}
+			bodyReader = zr
+		}
+	case "application/octet-stream", "application/x-tar":
+		// Use the stream without unzipping.
+	default:
+		return fmt.Errorf("unsupported content type: %s", ct)
+	}
+
+	return d.tarballToTree(bodyReader)
+}
+
+// tarballUrl fetches the url of the tarball for the directory's branch, or
+// for the commit the directory is pinned to.
+func tarballUrl(gfs *FS, d *dir) (string, error) {
+	if len(d.commit) > 0 {
+		return commitTarballUrl(gfs, d)
+	}
+
 	vars := map[string]any{
 		"owner":  d.org,
 		"repo":   d.repo,
-		"branch": "refs/heads/" + d.branch,
+		"branch": refPrefixBranches + d.branch,
 	}
 
 	/*
@@ -473,37 +766,47 @@ func getEntireGitDir(gfs *FS, d *dir) error {
 	}
 
 	if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
-		return err
+		return "", err
 	}
 
-	resp, err := gfs.httpClient.Get(query.Repo.Ref.Target.Commit.TarballUrl)
-	if err != nil {
-		return err
+	return query.Repo.Ref.Target.Commit.TarballUrl, nil
+}
+
+// commitTarballUrl fetches the url of the tarball for the commit the directory
+// is pinned to.
+func commitTarballUrl(gfs *FS, d *dir) (string, error) {
+	vars := map[string]any{
+		"owner": d.org,
+		"repo":  d.repo,
+		"exp":   d.commit,
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("http status code not 200: %d", resp.StatusCode)
+	/*
+	   query {
+	     repository(name: "repo", owner: "org") {
+	       object(expression: "4b825dc642cb6eb9a060e54bf8d69288fbee4904") {
+	         ... on Commit {
+	           tarballUrl
+	         }
+	       }
+	     }
+	   }
+	*/
+	var query struct {
+		Repo struct {
+			Object struct {
+				Commit struct {
+					TarballUrl string
+				} `graphql:"... on Commit"`
+			} `graphql:"object(expression: $exp)"`
+		} `graphql:"repository(name: $repo, owner: $owner)"`
 	}
 
-	ct := resp.Header.Get("Content-Type")
-	bodyReader := resp.Body
-	switch ct {
-	case "application/x-gzip", "application/gzip":
-		if !resp.Uncompressed {
-			zr, err := gzip.NewReader(bodyReader)
-			if err != nil {
-				return err
-			}
-			bodyReader = zr
-		}
-	case "application/octet-stream", "application/x-tar":
-		// Use the stream without unzipping.
-	default:
-		return fmt.Errorf("unsupported content type: %s", ct)
+	if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+		return "", err
 	}
 
-	return d.tarballToTree(bodyReader)
+	return query.Repo.Object.Commit.TarballUrl, nil
 }
 
 // getGitDir fetches a single directory via the github API. This isn't fast, but
@@ -515,7 +818,7 @@ func getGitDir(gfs *FS, d *dir) error {
 	vars := map[string]any{
 		"owner": d.org,
 		"repo":  d.repo,
-		"exp":   d.branch + ":" + path,
+		"exp":   d.rev() + ":" + path,
 	}
 
 	/*
@@ -552,7 +855,7 @@ func getGitDir(gfs *FS, d *dir) error {
 	}
 
 	for _, entry := range query.Repository.Object.Tree.Entries {
-		url := strings.Join([]string{gfs.rawUrl, d.org, d.repo, d.branch, path, entry.Name}, "/")
+		url := d.rawUrl(entry.Name)
 
 		switch entry.Mode {
 		case ghModeFile:
@@ -570,103 +873,3 @@ func getGitDir(gfs *FS, d *dir) error {
 
 	return nil
 }
-
-// getReleaseDir fetches the release information and makes it into a directory
-// structure that is linked to the filesystem
-func getReleaseDir(gfs *FS, d *dir) error {
-	vars := map[string]any{
-		"owner": d.org,
-		"repo":  d.repo,
-		"count": 100,
-		"after": (*string)(nil),
-	}
-
-	/*	query MyQuery {
-		  repository(name: "repo", owner: "org") {
-		    releases(first: 10, orderBy: {field: CREATED_AT, direction: DESC}) {
-		      edges {
-		        node {
-		          tag {
-		            name
-		          }
-		          isPrerelease
-		          isDraft
-				  createdAt
-		          description
-		          releaseAssets(first: 10) {
-		            edges {
-		              node {
-		                downloadUrl
-		                name
-		                size
-		              }
-		            }
-		          }
-		        }
-		      }
-		    }
-		  }
-		}
-	*/
-	more := true
-	for more {
-		var query struct {
-			Repository struct {
-				Releases struct {
-					PageInfo struct {
-						HasNextPage bool
-						EndCursor   string
-					}
-					Edges []struct {
-						Node struct {
-							Tag struct {
-								Name string
-							}
-							IsPrerelease  bool
-							IsDraft       bool
-							CreatedAt     string
-							Description   string
-							ReleaseAssets struct {
-								Edges []struct {
-									Node struct {
-										DownloadUrl string
-										Name        string
-										Size        int
-									}
-								}
-							} `graphql:"releaseAssets(first:100)"`
-						}
-					}
-				} `graphql:"releases(first: $count, orderBy: {field: CREATED_AT, direction: DESC}, after: $after)"`
-			} `graphql:"repository(name: $repo, owner: $owner)"`
-		}
-
-		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
-			return err
-		}
-
-		for _, edge := range query.Repository.Releases.Edges {
-			if edge.Node.IsDraft || edge.Node.IsPrerelease {
-				continue
-			}
-
-			tag := edge.Node.Tag.Name
-			desc := edge.Node.Description
-
-			relDir := d.newDir(tag)
-
-			relDir.addFile("description.md", withContent([]byte(desc)))
-
-			for _, asset := range edge.Node.ReleaseAssets.Edges {
-				relDir.addFile(asset.Node.Name,
-					withSize(asset.Node.Size),
-					withUrl(asset.Node.DownloadUrl))
-			}
-		}
-
-		more = query.Repository.Releases.PageInfo.HasNextPage
-		vars["after"] = query.Repository.Releases.PageInfo.EndCursor
-	}
-
-	return nil
-}
@@ -6,8 +6,11 @@ package githubfs
 import (
 	_ "embed"
 	"fmt"
+	"io"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"strings"
 	"testing"
 
@@ -21,6 +24,9 @@ func TestNew(t *testing.T) {
 		opts          []Option
 		ghUrl         string
 		rawUrl        string
+		restUrl       string
+		gistUrl       string
+		containerUrl  string
 		nilHttpClient bool
 		threshold     int
 		inputs        []input
@@ -28,10 +34,13 @@ func TestNew(t *testing.T) {
 		{
 			description: "basic test",
 		}, {
-			description: "use github enterprise",
-			ghUrl:       "https://example.com/api/graphql",
-			rawUrl:      "https://example.com/raw",
-			opts:        []Option{WithGithubEnterprise("https://example.com", "3.3")},
+			description:  "use github enterprise",
+			ghUrl:        "https://example.com/api/graphql",
+			rawUrl:       "https://example.com/raw",
+			restUrl:      "https://example.com/api/v3",
+			gistUrl:      "https://example.com/gist",
+			containerUrl: "https://containers.example.com",
+			opts:         []Option{WithGithubEnterprise("https://example.com", "3.3")},
 		}, {
 			description:   "different http client",
 			nilHttpClient: true,
@@ -69,6 +78,25 @@ func TestNew(t *testing.T) {
 					allowArchived: true,
 				},
 			},
+		}, {
+			description: "specify repos with branch globs.",
+			opts: []Option{
+				WithBranches("org", "repo"),
+				WithBranches("cat", "repo", "release/*", "main"),
+			},
+			inputs: []input{
+				{
+					org:           "org",
+					repo:          "repo",
+					allowArchived: true,
+					allBranches:   true,
+				}, {
+					org:           "cat",
+					repo:          "repo",
+					allowArchived: true,
+					globs:         []string{"release/*", "main"},
+				},
+			},
 		}, {
 			description: "specify orgs and repos using WithSlug.",
 			opts: []Option{
@@ -147,6 +175,15 @@ func TestNew(t *testing.T) {
 			if len(tc.rawUrl) != 0 {
 				assert.Equal(tc.rawUrl, gfs.rawUrl)
 			}
+			if len(tc.restUrl) != 0 {
+				assert.Equal(tc.restUrl, gfs.restUrl)
+			}
+			if len(tc.gistUrl) != 0 {
+				assert.Equal(tc.gistUrl, gfs.gistUrl)
+			}
+			if len(tc.containerUrl) != 0 {
+				assert.Equal(tc.containerUrl, gfs.containerUrl)
+			}
 			if tc.nilHttpClient {
 				assert.Nil(gfs.httpClient)
 			} else {
@@ -162,22 +199,15 @@ func TestNew(t *testing.T) {
 				assert.Equal(tc.inputs[i].repo, gfs.inputs[i].repo)
 				assert.Equal(tc.inputs[i].branch, gfs.inputs[i].branch)
 				assert.Equal(tc.inputs[i].allowArchived, gfs.inputs[i].allowArchived)
+				assert.Equal(tc.inputs[i].allBranches, gfs.inputs[i].allBranches)
+				assert.Equal(tc.inputs[i].globs, gfs.inputs[i].globs)
 			}
 		})
 	}
 }
 
 func TestMostThings(t *testing.T) {
-	tests := []struct {
-		description string
-		opts        []Option
-		statusCode  []int
-		payload     []string
-		ct          []string
-		expectErr   bool
-		expect      []string
-		unexpected  []string
-	}{
+	tests := []fsTest{
 		{
 			description: "fetch a single repo test",
 			opts:        []Option{WithRepo("org", "repo")},
@@ -325,51 +355,33 @@ func TestMostThings(t *testing.T) {
 		}, {},
 	}
 
+	runFSTests(t, tests)
+}
+
+// fsTest describes a filesystem test run against a fake github server that
+// replies with each payload in order.
+type fsTest struct {
+	description string
+	opts        []Option
+	statusCode  []int
+	payload     []string
+	ct          []string
+	expectErr   bool
+	expect      []string
+	unexpected  []string
+	contents    map[string]string
+	requests    []string
+	bodies      []string
+}
+
+// runFSTests runs each of the filesystem tests against a fake github server.
+func runFSTests(t *testing.T, tests []fsTest) {
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
 			assert := assert.New(t)
 			require := require.New(t)
 
-			// Figure out the address before we start so we can replace other URLs
-			// in the content.
-			i := 0
-			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
-			url := "http://" + server.Listener.Addr().String()
-
-			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				var statusSent bool
-				if len(tc.ct) > 0 {
-					if i < len(tc.ct) && len(tc.ct[i]) > 0 {
-						w.Header().Add("Content-Type", tc.ct[i])
-					}
-				}
-
-				if len(tc.statusCode) > 0 {
-					if i < len(tc.statusCode) {
-						if tc.statusCode[i] != 0 {
-							w.WriteHeader(tc.statusCode[i])
-							statusSent = true
-						}
-					} else {
-						statusSent = true
-						w.WriteHeader(500)
-					}
-				}
-				if i < len(tc.payload) {
-					payload := strings.ReplaceAll(tc.payload[i], "OVERWRITEURL", url)
-					_, _ = fmt.Fprint(w, payload)
-				} else {
-					if !statusSent {
-						w.WriteHeader(500)
-					}
-				}
-				i++
-			})
-
-			server.Start()
-
-			opts := append(tc.opts, withTestURL(server.URL))
-			gfs := New(opts...)
+			gfs, requests, bodies := newTestFS(t, tc)
 			require.NotNil(gfs)
 
 			for _, path := range tc.expect {
@@ -389,10 +401,80 @@ func TestMostThings(t *testing.T) {
 				assert.Nil(f)
 			}
 
+			// Read the contents in a stable order so the payloads line up.
+			paths := make([]string, 0, len(tc.contents))
+			for path := range tc.contents {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			for _, path := range paths {
+				got, err := fs.ReadFile(gfs, path)
+				assert.NoError(err)
+				assert.Equal(tc.contents[path], string(got))
+			}
+
+			if tc.requests != nil {
+				assert.Equal(tc.requests, *requests)
+			}
+
+			for _, body := range tc.bodies {
+				assert.Contains(strings.Join(*bodies, "\n"), body)
+			}
 		})
 	}
 }
 
+// newTestFS creates a filesystem connected to a fake github server that replies
+// with each payload in order.  The requests made to the server and the request
+// bodies are recorded.
+func newTestFS(t *testing.T, tc fsTest) (*FS, *[]string, *[]string) {
+	// Figure out the address before we start so we can replace other URLs
+	// in the content.
+	i := 0
+	var requests, bodies []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := "http://" + server.Listener.Addr().String()
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		var statusSent bool
+		if len(tc.ct) > 0 {
+			if i < len(tc.ct) && len(tc.ct[i]) > 0 {
+				w.Header().Add("Content-Type", tc.ct[i])
+			}
+		}
+
+		if len(tc.statusCode) > 0 {
+			if i < len(tc.statusCode) {
+				if tc.statusCode[i] != 0 {
+					w.WriteHeader(tc.statusCode[i])
+					statusSent = true
+				}
+			} else {
+				statusSent = true
+				w.WriteHeader(500)
+			}
+		}
+		if i < len(tc.payload) {
+			payload := strings.ReplaceAll(tc.payload[i], "OVERWRITEURL", url)
+			_, _ = fmt.Fprint(w, payload)
+		} else {
+			if !statusSent {
+				w.WriteHeader(500)
+			}
+		}
+		i++
+	})
+
+	server.Start()
+	t.Cleanup(server.Close)
+
+	opts := append(tc.opts, withTestURL(server.URL))
+	return New(opts...), &requests, &bodies
+}
+
 var singleRepoReponse = `{
   "data": {
     "repository": {
@@ -620,9 +702,7 @@ var releaseResponse = `{
         "edges": [
           {
             "node": {
-              "tag": {
-                "name": "v0.6.8"
-              },
+              "tagName": "v0.6.8",
               "isPrerelease": true,
               "isDraft": false,
               "createdAt": "2022-09-06T20:21:35Z",
@@ -656,9 +736,7 @@ var releaseResponse = `{
           },
           {
             "node": {
-              "tag": {
-                "name": "v0.6.7"
-              },
+              "tagName": "v0.6.7",
               "isPrerelease": false,
               "isDraft": false,
               "createdAt": "2022-08-26T22:53:33Z",
@@ -0,0 +1,266 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	fileNameCommitInfo   = "commit.json"
+	fileNameCommitDiff   = "diff.patch"
+	defaultHistoryDepth  = 100
+	signatureStateNoSign = "UNSIGNED"
+)
+
+// WithHistoryDepth sets the number of recent commits listed for each branch in
+// the history directory.  The default is 100.
+func WithHistoryDepth(n int) Option {
+	return func(gfs *FS) {
+		gfs.historyDepth = n
+	}
+}
+
+// commitPerson is the author or committer of a commit.
+type commitPerson struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Login string    `json:"login,omitempty"`
+	Date  time.Time `json:"date"`
+}
+
+// commitInfo is the metadata about a commit written to commit.json.
+type commitInfo struct {
+	Sha            string       `json:"sha"`
+	Message        string       `json:"message"`
+	Author         commitPerson `json:"author"`
+	Committer      commitPerson `json:"committer"`
+	Parents        []string     `json:"parents"`
+	Verified       bool         `json:"verified"`
+	SignatureState string       `json:"signatureState"`
+	Url            string       `json:"url"`
+}
+
+// gitActor is the author or committer of a commit in queries.
+type gitActor struct {
+	Name  string
+	Email string
+	Date  time.Time
+	User  struct {
+		Login string
+	}
+}
+
+// login returns the github login of the actor if known, otherwise the name.
+func (a gitActor) login() string {
+	if len(a.User.Login) > 0 {
+		return a.User.Login
+	}
+	return a.Name
+}
+
+// toPerson converts the actor into the form written to commit.json.
+func (a gitActor) toPerson() commitPerson {
+	return commitPerson{
+		Name:  a.Name,
+		Email: a.Email,
+		Login: a.User.Login,
+		Date:  a.Date,
+	}
+}
+
+// getHistoryDir adds a directory for each branch in the git directory that is
+// pinned to a commit.  The commits of each branch are listed when the branch
+// directory is used.
+func getHistoryDir(gfs *FS, d *dir) error {
+	git, ok := d.parent.children[dirNameGit].(*dir)
+	if !ok {
+		return nil
+	}
+	if err := git.fetch(); err != nil {
+		return err
+	}
+
+	addHistoryBranches(d, git, "")
+	return nil
+}
+
+// addHistoryBranches walks the directories of the branch names in the git
+// directory and adds the same branches to the history directory.  Only the
+// directories named after their branch came from the branch listing, the
+// others like "main@2026-03-01" were looked up.
+func addHistoryBranches(d, git *dir, prefix string) {
+	for name, child := range git.children {
+		sub, ok := child.(*dir)
+		if !ok {
+			continue
+		}
+
+		if len(sub.branch) == 0 {
+			addHistoryBranches(d, sub, prefix+name+"/")
+			continue
+		}
+		if sub.branch != prefix+name {
+			continue
+		}
+
+		d.mkref(prefix+name,
+			withBranch(sub.branch),
+			withCommit(sub.commit),
+			withFetcher(getBranchHistory),
+			notInPath())
+	}
+}
+
+// getBranchHistory lists the recent commits of the branch up to the history
+// depth and makes each into a directory with the commit metadata and diff.
+func getBranchHistory(gfs *FS, d *dir) error {
+	remaining := gfs.historyDepth
+	vars := map[string]any{
+		"owner": d.org,
+		"repo":  d.repo,
+		"exp":   d.rev(),
+		"count": pageSize(remaining),
+		"after": (*string)(nil),
+	}
+
+	/*
+		query {
+		  repository(name: "repo", owner: "org") {
+		    object(expression: "main") {
+		      ... on Commit {
+		        history(first: 100) {
+		          edges {
+		            node {
+		              oid
+		              message
+		              url
+		              author {
+		                name
+		                email
+		                date
+		                user {
+		                  login
+		                }
+		              }
+		              committer {
+		                name
+		                email
+		                date
+		                user {
+		                  login
+		                }
+		              }
+		              parents(first: 100) {
+		                nodes {
+		                  oid
+		                }
+		              }
+		              signature {
+		                isValid
+		                state
+		              }
+		            }
+		          }
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	for remaining > 0 {
+		var query struct {
+			Repository struct {
+				Object struct {
+					Commit struct {
+						History struct {
+							PageInfo struct {
+								HasNextPage bool
+								EndCursor   string
+							}
+							Edges []struct {
+								Node struct {
+									Oid       string
+									Message   string
+									Url       string
+									Author    gitActor
+									Committer gitActor
+									Parents   struct {
+										Nodes []struct {
+											Oid string
+										}
+									} `graphql:"parents(first: 100)"`
+									Signature *struct {
+										IsValid bool
+										State   string
+									}
+								}
+							}
+						} `graphql:"history(first: $count, after: $after)"`
+					} `graphql:"... on Commit"`
+				} `graphql:"object(expression: $exp)"`
+			} `graphql:"repository(name: $repo, owner: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return err
+		}
+
+		history := query.Repository.Object.Commit.History
+		for _, edge := range history.Edges {
+			c := edge.Node
+			info := commitInfo{
+				Sha:            c.Oid,
+				Message:        c.Message,
+				Author:         c.Author.toPerson(),
+				Committer:      c.Committer.toPerson(),
+				Parents:        []string{},
+				SignatureState: signatureStateNoSign,
+				Url:            c.Url,
+			}
+			for _, parent := range c.Parents.Nodes {
+				info.Parents = append(info.Parents, parent.Oid)
+			}
+			if c.Signature != nil {
+				info.Verified = c.Signature.IsValid
+				info.SignatureState = c.Signature.State
+			}
+
+			buf, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			when := c.Committer.Date
+			commitDir := d.newDir(c.Oid, withCommit(c.Oid), withDirModTime(when), notInPath())
+			commitDir.addFile(fileNameCommitInfo, withContent(buf), withModTime(when))
+			commitDir.addFile(fileNameCommitDiff,
+				withUrl(strings.Join([]string{gfs.restUrl, "repos", d.org, d.repo, "commits", c.Oid}, "/")),
+				withAccept("application/vnd.github.diff"),
+				withUnknownSize(),
+				withModTime(when))
+		}
+
+		remaining -= len(history.Edges)
+		if !history.PageInfo.HasNextPage || len(history.Edges) == 0 {
+			break
+		}
+		vars["count"] = pageSize(remaining)
+		vars["after"] = history.PageInfo.EndCursor
+	}
+
+	return nil
+}
+
+// pageSize returns the number of items to ask for in the next page when only
+// the remaining number are wanted.
+func pageSize(remaining int) int {
+	if remaining < 100 {
+		return remaining
+	}
+	return 100
+}
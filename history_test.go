@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "fetch the history of a branch up to the depth",
+			opts:        []Option{WithRepo("org", "repo"), WithHistoryDepth(3)},
+			payload:     []string{singleRepoWithHeadReponse, historyResponse001, historyResponse002},
+			expect:      []string{"org/repo/history/main/cccccccccccccccccccccccccccccccccccccccc"},
+			contents: map[string]string{
+				"org/repo/history/main/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa/commit.json": commitAJson,
+			},
+			bodies: []string{
+				`"exp":"1111111111111111111111111111111111111111"`,
+				`"count":3`,
+				`"count":1`,
+			},
+		}, {
+			description: "fetch the diff of a commit",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithHeadReponse, historyResponse002, pullDiffResponse},
+			contents: map[string]string{
+				"org/repo/history/main/cccccccccccccccccccccccccccccccccccccccc/diff.patch": pullDiffResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"GET /repos/org/repo/commits/cccccccccccccccccccccccccccccccccccccccc",
+			},
+		}, {
+			description: "the history includes the discovered branches",
+			opts:        []Option{WithBranches("org", "repo", "release/*")},
+			payload:     []string{singleRepoReponse, branchesResponse001, branchesResponse002, historyResponse002},
+			expect:      []string{"org/repo/history/release/2.0"},
+			unexpected:  []string{"org/repo/history/main", "org/repo/history/feature/x"},
+		}, {
+			description: "fetch the history, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithHeadReponse, invalidJsonResponse},
+			expect:      []string{"org/repo/history/main"},
+			expectErr:   true,
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+func TestHistorySkipsLookedUpBranches(t *testing.T) {
+	require := require.New(t)
+
+	gfs, _, _ := newTestFS(t, fsTest{
+		opts: []Option{WithRepo("org", "repo", "release/2.0"), WithThresholdInKB(0)},
+		payload: []string{singleRepoReponse, historyResponse, baseDirectoryResponse,
+			historyResponse, baseDirectoryResponse},
+	})
+
+	for _, name := range []string{"org/repo/git/main@2026-03-01", "org/repo/git/release/1.0@2026-03-01"} {
+		_, err := fs.Stat(gfs, name)
+		require.NoError(err)
+	}
+
+	entries, err := fs.ReadDir(gfs, "org/repo/history")
+	require.NoError(err)
+	assert.Equal(t, []string{"release"}, entryNames(entries))
+
+	entries, err = fs.ReadDir(gfs, "org/repo/history/release")
+	require.NoError(err)
+	assert.Equal(t, []string{"2.0"}, entryNames(entries))
+}
+
+var historyResponse001 = `{
+  "data": {
+    "repository": {
+      "object": {
+        "history": {
+          "pageInfo": {
+            "hasNextPage": true,
+            "endCursor": "aGlzdG9yeToy"
+          },
+          "edges": [
+            {
+              "node": {
+                "oid": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+                "message": "Fix the config loader\n\nIt didn't load.",
+                "url": "https://github.com/org/repo/commit/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+                "author": {
+                  "name": "Mona Lisa",
+                  "email": "mona@example.com",
+                  "date": "2023-05-02T10:00:00Z",
+                  "user": {
+                    "login": "monalisa"
+                  }
+                },
+                "committer": {
+                  "name": "GitHub",
+                  "email": "noreply@github.com",
+                  "date": "2023-05-02T11:00:00Z",
+                  "user": null
+                },
+                "parents": {
+                  "nodes": [
+                    { "oid": "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb" }
+                  ]
+                },
+                "signature": {
+                  "isValid": true,
+                  "state": "VALID"
+                }
+              }
+            },
+            {
+              "node": {
+                "oid": "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+                "message": "Add the config loader",
+                "url": "https://github.com/org/repo/commit/bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+                "author": {
+                  "name": "Mona Lisa",
+                  "email": "mona@example.com",
+                  "date": "2023-05-01T10:00:00Z",
+                  "user": {
+                    "login": "monalisa"
+                  }
+                },
+                "committer": {
+                  "name": "Mona Lisa",
+                  "email": "mona@example.com",
+                  "date": "2023-05-01T10:00:00Z",
+                  "user": {
+                    "login": "monalisa"
+                  }
+                },
+                "parents": {
+                  "nodes": [
+                    { "oid": "cccccccccccccccccccccccccccccccccccccccc" }
+                  ]
+                },
+                "signature": null
+              }
+            }
+          ]
+        }
+      }
+    }
+  }
+}`
+
+var historyResponse002 = `{
+  "data": {
+    "repository": {
+      "object": {
+        "history": {
+          "pageInfo": {
+            "hasNextPage": false,
+            "endCursor": "aGlzdG9yeToz"
+          },
+          "edges": [
+            {
+              "node": {
+                "oid": "cccccccccccccccccccccccccccccccccccccccc",
+                "message": "Initial commit",
+                "url": "https://github.com/org/repo/commit/cccccccccccccccccccccccccccccccccccccccc",
+                "author": {
+                  "name": "Mona Lisa",
+                  "email": "mona@example.com",
+                  "date": "2023-04-01T10:00:00Z",
+                  "user": {
+                    "login": "monalisa"
+                  }
+                },
+                "committer": {
+                  "name": "Mona Lisa",
+                  "email": "mona@example.com",
+                  "date": "2023-04-01T10:00:00Z",
+                  "user": {
+                    "login": "monalisa"
+                  }
+                },
+                "parents": {
+                  "nodes": []
+                },
+                "signature": null
+              }
+            }
+          ]
+        }
+      }
+    }
+  }
+}`
+
+var commitAJson = `{
+  "sha": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+  "message": "Fix the config loader\n\nIt didn't load.",
+  "author": {
+    "name": "Mona Lisa",
+    "email": "mona@example.com",
+    "login": "monalisa",
+    "date": "2023-05-02T10:00:00Z"
+  },
+  "committer": {
+    "name": "GitHub",
+    "email": "noreply@github.com",
+    "date": "2023-05-02T11:00:00Z"
+  },
+  "parents": [
+    "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+  ],
+  "verified": true,
+  "signatureState": "VALID",
+  "url": "https://github.com/org/repo/commit/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+}`
@@ -0,0 +1,291 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// issueState is the github IssueState enum used in queries.
+type issueState string
+
+// GetGraphQLType returns the name of the github type.
+func (issueState) GetGraphQLType() string {
+	return "IssueState"
+}
+
+// WithIssueFilter limits the issues included in the issues directories.  The
+// state may be "open", "closed" or "" for both.  If labels are specified only
+// the issues with at least one of the labels are included.
+func WithIssueFilter(state string, labels ...string) Option {
+	return func(gfs *FS) {
+		gfs.issueStates = nil
+		if len(state) > 0 {
+			gfs.issueStates = []issueState{issueState(strings.ToUpper(state))}
+		}
+		gfs.issueLabels = labels
+	}
+}
+
+// issueComment is a single comment on an issue.
+type issueComment struct {
+	Author struct {
+		Login string
+	}
+	Body      string
+	CreatedAt time.Time
+}
+
+// issueComments is a page of comments on an issue.
+type issueComments struct {
+	PageInfo struct {
+		HasNextPage bool
+		EndCursor   string
+	}
+	Edges []struct {
+		Node issueComment
+	}
+}
+
+// getIssuesDir fetches the issues and makes each into a markdown file with the
+// details in the front matter followed by the body and the comments.
+func getIssuesDir(gfs *FS, d *dir) error {
+	vars := map[string]any{
+		"owner":  d.org,
+		"repo":   d.repo,
+		"count":  100,
+		"after":  (*string)(nil),
+		"states": (*[]issueState)(nil),
+		"labels": (*[]string)(nil),
+	}
+	if len(gfs.issueStates) > 0 {
+		vars["states"] = &gfs.issueStates
+	}
+	if len(gfs.issueLabels) > 0 {
+		vars["labels"] = &gfs.issueLabels
+	}
+
+	/*
+		query {
+		  repository(name: "repo", owner: "org") {
+		    issues(first: 100, states: [OPEN], labels: ["bug"], orderBy: {field: CREATED_AT, direction: DESC}) {
+		      edges {
+		        node {
+		          number
+		          title
+		          state
+		          body
+		          url
+		          createdAt
+		          updatedAt
+		          closedAt
+		          author {
+		            login
+		          }
+		          labels(first: 100) {
+		            edges {
+		              node {
+		                name
+		              }
+		            }
+		          }
+		          assignees(first: 100) {
+		            edges {
+		              node {
+		                login
+		              }
+		            }
+		          }
+		          comments(first: 100) {
+		            edges {
+		              node {
+		                author {
+		                  login
+		                }
+		                body
+		                createdAt
+		              }
+		            }
+		          }
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	more := true
+	for more {
+		var query struct {
+			Repository struct {
+				Issues struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+					Edges []struct {
+						Node struct {
+							Number    int
+							Title     string
+							State     string
+							Body      string
+							Url       string
+							CreatedAt time.Time
+							UpdatedAt time.Time
+							ClosedAt  *time.Time
+							Author    struct {
+								Login string
+							}
+							Labels struct {
+								Edges []struct {
+									Node struct {
+										Name string
+									}
+								}
+							} `graphql:"labels(first: 100)"`
+							Assignees struct {
+								Edges []struct {
+									Node struct {
+										Login string
+									}
+								}
+							} `graphql:"assignees(first: 100)"`
+							Comments issueComments `graphql:"comments(first: 100)"`
+						}
+					}
+				} `graphql:"issues(first: $count, after: $after, states: $states, labels: $labels, orderBy: {field: CREATED_AT, direction: DESC})"`
+			} `graphql:"repository(name: $repo, owner: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return err
+		}
+
+		for _, edge := range query.Repository.Issues.Edges {
+			issue := edge.Node
+
+			labels := []string{}
+			for _, label := range issue.Labels.Edges {
+				labels = append(labels, label.Node.Name)
+			}
+			assignees := []string{}
+			for _, assignee := range issue.Assignees.Edges {
+				assignees = append(assignees, assignee.Node.Login)
+			}
+
+			var comments []issueComment
+			for _, comment := range issue.Comments.Edges {
+				comments = append(comments, comment.Node)
+			}
+			if issue.Comments.PageInfo.HasNextPage {
+				rest, err := getIssueComments(gfs, d, issue.Number, issue.Comments.PageInfo.EndCursor)
+				if err != nil {
+					return err
+				}
+				comments = append(comments, rest...)
+			}
+
+			fm := frontMatter{
+				{key: "number", value: issue.Number},
+				{key: "title", value: issue.Title},
+				{key: "state", value: issue.State},
+				{key: "author", value: issue.Author.Login},
+				{key: "labels", value: labels},
+				{key: "assignees", value: assignees},
+				{key: "url", value: issue.Url},
+				{key: "createdAt", value: issue.CreatedAt},
+				{key: "updatedAt", value: issue.UpdatedAt},
+				{key: "closedAt", value: issue.ClosedAt},
+			}
+
+			d.addFile(strconv.Itoa(issue.Number)+".md",
+				withContent(renderIssue(fm, issue.Body, comments)),
+				withModTime(issue.UpdatedAt))
+		}
+
+		more = query.Repository.Issues.PageInfo.HasNextPage
+		vars["after"] = query.Repository.Issues.PageInfo.EndCursor
+	}
+
+	return nil
+}
+
+// getIssueComments fetches the rest of the comments on an issue that has too
+// many comments to fetch with the issue.
+func getIssueComments(gfs *FS, d *dir, number int, after string) ([]issueComment, error) {
+	vars := map[string]any{
+		"owner":  d.org,
+		"repo":   d.repo,
+		"number": number,
+		"count":  100,
+		"after":  &after,
+	}
+
+	var comments []issueComment
+	more := true
+	for more {
+		var query struct {
+			Repository struct {
+				Issue struct {
+					Comments issueComments `graphql:"comments(first: $count, after: $after)"`
+				} `graphql:"issue(number: $number)"`
+			} `graphql:"repository(name: $repo, owner: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return nil, err
+		}
+
+		for _, edge := range query.Repository.Issue.Comments.Edges {
+			comments = append(comments, edge.Node)
+		}
+
+		more = query.Repository.Issue.Comments.PageInfo.HasNextPage
+		vars["after"] = query.Repository.Issue.Comments.PageInfo.EndCursor
+	}
+
+	return comments, nil
+}
+
+// renderIssue renders the front matter, body and comments into a markdown
+// file.
+func renderIssue(fm frontMatter, body string, comments []issueComment) []byte {
+	var b strings.Builder
+
+	b.WriteString(fm.String())
+	if body = strings.TrimRight(body, "\n"); len(body) > 0 {
+		b.WriteString("\n")
+		b.WriteString(body)
+		b.WriteString("\n")
+	}
+
+	if len(comments) > 0 {
+		b.WriteString("\n## Comments\n")
+	}
+	for _, comment := range comments {
+		writeComment(&b, "###", comment, "")
+	}
+
+	return []byte(b.String())
+}
+
+// writeComment writes a comment as a markdown section with the heading
+// followed by the author and time, and the optional note.
+func writeComment(b *strings.Builder, heading string, comment issueComment, note string) {
+	b.WriteString("\n")
+	b.WriteString(heading)
+	b.WriteString(" @")
+	b.WriteString(comment.Author.Login)
+	b.WriteString(" on ")
+	b.WriteString(comment.CreatedAt.UTC().Format(time.RFC3339))
+	if len(note) > 0 {
+		b.WriteString(" ")
+		b.WriteString(note)
+	}
+	b.WriteString("\n\n")
+	b.WriteString(strings.TrimRight(comment.Body, "\n"))
+	b.WriteString("\n")
+}
@@ -0,0 +1,253 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"testing"
+)
+
+func TestIssues(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "fetch the issues",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithIssuesReponse, issuesResponse001, issueCommentsResponse, issuesResponse002},
+			expect:      []string{"org/repo/issues/11.md"},
+			unexpected:  []string{"org/repo/issues/10.md"},
+			contents: map[string]string{
+				"org/repo/issues/11.md": issue11Markdown,
+				"org/repo/issues/12.md": issue12Markdown,
+			},
+			bodies: []string{`"states":null`, `"labels":null`},
+		}, {
+			description: "fetch the closed issues with labels",
+			opts:        []Option{WithRepo("org", "repo"), WithIssueFilter("closed", "bug", "p1")},
+			payload:     []string{singleRepoWithIssuesReponse, issuesResponse002},
+			expect:      []string{"org/repo/issues/11.md"},
+			bodies:      []string{`"states":["CLOSED"]`, `"labels":["bug","p1"]`, `$states:[IssueState!]`},
+		}, {
+			description: "a repo without issues enabled has no issues directory",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithIssuesDisabledReponse},
+			unexpected:  []string{"org/repo/issues"},
+		}, {
+			description: "fetch the issues, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithIssuesReponse, invalidJsonResponse},
+			expect:      []string{"org/repo/issues/11.md"},
+			expectErr:   true,
+		}, {
+			description: "fetch the issue comments, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithIssuesReponse, issuesResponse001, invalidJsonResponse},
+			expect:      []string{"org/repo/issues/12.md"},
+			expectErr:   true,
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+var singleRepoWithIssuesReponse = `{
+  "data": {
+    "repository": {
+      "diskUsage": 18,
+      "isArchived": false,
+      "isDisabled": false,
+      "hasIssuesEnabled": true,
+      "nameWithOwner": "org/repo",
+      "defaultBranchRef": {
+        "name": "main"
+      },
+      "issues": {
+        "totalCount": 2
+      },
+      "releases": {
+        "totalCount": 0
+      }
+    }
+  }
+}`
+
+var singleRepoWithIssuesDisabledReponse = `{
+  "data": {
+    "repository": {
+      "diskUsage": 18,
+      "isArchived": false,
+      "isDisabled": false,
+      "hasIssuesEnabled": false,
+      "nameWithOwner": "org/repo",
+      "defaultBranchRef": {
+        "name": "main"
+      },
+      "issues": {
+        "totalCount": 2
+      },
+      "releases": {
+        "totalCount": 0
+      }
+    }
+  }
+}`
+
+var issuesResponse001 = `{
+  "data": {
+    "repository": {
+      "issues": {
+        "edges": [
+          {
+            "node": {
+              "number": 12,
+              "title": "Outage: config did not load",
+              "state": "OPEN",
+              "body": "See INC-1234.\n",
+              "url": "https://github.com/org/repo/issues/12",
+              "createdAt": "2023-05-01T10:00:00Z",
+              "updatedAt": "2023-05-03T10:00:00Z",
+              "closedAt": null,
+              "author": {
+                "login": "octocat"
+              },
+              "labels": {
+                "edges": [
+                  { "node": { "name": "incident" } }
+                ]
+              },
+              "assignees": {
+                "edges": [
+                  { "node": { "login": "hubot" } }
+                ]
+              },
+              "comments": {
+                "edges": [
+                  {
+                    "node": {
+                      "author": { "login": "hubot" },
+                      "body": "Looking.",
+                      "createdAt": "2023-05-01T11:00:00Z"
+                    }
+                  }
+                ],
+                "pageInfo": {
+                  "endCursor": "MQ",
+                  "hasNextPage": true
+                }
+              }
+            }
+          }
+        ],
+        "pageInfo": {
+          "endCursor": "MQ",
+          "hasNextPage": true
+        }
+      }
+    }
+  }
+}`
+
+var issueCommentsResponse = `{
+  "data": {
+    "repository": {
+      "issue": {
+        "comments": {
+          "edges": [
+            {
+              "node": {
+                "author": { "login": "octocat" },
+                "body": "Fixed by INC-1235.",
+                "createdAt": "2023-05-02T11:00:00Z"
+              }
+            }
+          ],
+          "pageInfo": {
+            "endCursor": "Mg",
+            "hasNextPage": false
+          }
+        }
+      }
+    }
+  }
+}`
+
+var issuesResponse002 = `{
+  "data": {
+    "repository": {
+      "issues": {
+        "edges": [
+          {
+            "node": {
+              "number": 11,
+              "title": "Typo",
+              "state": "CLOSED",
+              "body": "",
+              "url": "https://github.com/org/repo/issues/11",
+              "createdAt": "2023-04-01T10:00:00Z",
+              "updatedAt": "2023-04-02T10:00:00Z",
+              "closedAt": "2023-04-02T10:00:00Z",
+              "author": {
+                "login": "hubot"
+              },
+              "labels": {
+                "edges": []
+              },
+              "assignees": {
+                "edges": []
+              },
+              "comments": {
+                "edges": [],
+                "pageInfo": {
+                  "endCursor": null,
+                  "hasNextPage": false
+                }
+              }
+            }
+          }
+        ],
+        "pageInfo": {
+          "endCursor": "Mg",
+          "hasNextPage": false
+        }
+      }
+    }
+  }
+}`
+
+var issue12Markdown = `---
+number: 12
+title: "Outage: config did not load"
+state: "OPEN"
+author: "octocat"
+labels: ["incident"]
+assignees: ["hubot"]
+url: "https://github.com/org/repo/issues/12"
+createdAt: 2023-05-01T10:00:00Z
+updatedAt: 2023-05-03T10:00:00Z
+---
+
+See INC-1234.
+
+## Comments
+
+### @hubot on 2023-05-01T11:00:00Z
+
+Looking.
+
+### @octocat on 2023-05-02T11:00:00Z
+
+Fixed by INC-1235.
+`
+
+var issue11Markdown = `---
+number: 11
+title: "Typo"
+state: "CLOSED"
+author: "hubot"
+labels: []
+assignees: []
+url: "https://github.com/org/repo/issues/11"
+createdAt: 2023-04-01T10:00:00Z
+updatedAt: 2023-04-02T10:00:00Z
+closedAt: 2023-04-02T10:00:00Z
+---
+`
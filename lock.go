@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Manifest records the commit each branch of the filesystem was read at.  It
+// is safe to serialize, and can be passed to WithLock() so a later filesystem
+// reads exactly the same commits.
+type Manifest struct {
+	Branches []LockedBranch `json:"branches"`
+}
+
+// LockedBranch is the commit a branch of a repository is pinned to.
+type LockedBranch struct {
+	Org    string `json:"org"`
+	Repo   string `json:"repo"`
+	Branch string `json:"branch"`
+	Commit string `json:"commit"`
+}
+
+// slug returns the branch in the "org/repo:branch" form.
+func (lb LockedBranch) slug() string {
+	return lockKey(lb.Org, lb.Repo, lb.Branch)
+}
+
+// WithLock pins the branches listed in the manifest to the recorded commits
+// instead of the current head of each branch.  Branches not in the manifest
+// are resolved normally.
+func WithLock(m Manifest) Option {
+	return func(gfs *FS) {
+		for _, lb := range m.Branches {
+			gfs.pins[lb.slug()] = lb.Commit
+		}
+	}
+}
+
+// Lock returns the manifest of the commits each branch was resolved to.  The
+// configured branches are resolved when the filesystem connects to github.
+// Branches discovered via WithBranches() are included once they are listed.
+func (gfs *FS) Lock() (Manifest, error) {
+	if err := gfs.connect(); err != nil {
+		return Manifest{}, fmt.Errorf("lock error connecting: %w", err)
+	}
+
+	keys := make([]string, 0, len(gfs.lock))
+	for key := range gfs.lock {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	m := Manifest{
+		Branches: make([]LockedBranch, 0, len(keys)),
+	}
+	for _, key := range keys {
+		slug, branch, _ := strings.Cut(key, ":")
+		org, repo, _ := strings.Cut(slug, "/")
+		m.Branches = append(m.Branches, LockedBranch{
+			Org:    org,
+			Repo:   repo,
+			Branch: branch,
+			Commit: gfs.lock[key],
+		})
+	}
+
+	return m, nil
+}
+
+// pin determines the commit a branch is read at.  A commit from WithLock()
+// wins, then the first commit the branch was resolved to, then the commit
+// from WithAsOf(), then the head commit passed in.  The result is recorded for
+// Lock().
+func (gfs *FS) pin(org, repo, branch, head string) (string, error) {
+	key := lockKey(org, repo, branch)
+
+	oid, found := gfs.pinned(key)
+	if !found {
+		oid = head
+		if !gfs.asOf.IsZero() {
+			var err error
+			oid, err = resolveAsOf(gfs, org, repo, branch, gfs.asOf)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if len(oid) > 0 {
+		gfs.lock[key] = oid
+	}
+
+	return oid, nil
+}
+
+// pinned returns the commit from WithLock() or the first commit the branch was
+// resolved to, if there is one.
+func (gfs *FS) pinned(key string) (string, bool) {
+	if oid, found := gfs.pins[key]; found {
+		return oid, true
+	}
+	oid, found := gfs.lock[key]
+	return oid, found
+}
+
+// lockKey returns the key used for a branch in the "org/repo:branch" form.
+func lockKey(org, repo, branch string) string {
+	return org + "/" + repo + ":" + branch
+}
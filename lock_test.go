@@ -0,0 +1,371 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"encoding/json"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	tests := []struct {
+		description string
+		tc          fsTest
+		open        []string
+		expect      Manifest
+		expectErr   bool
+	}{
+		{
+			description: "lock the default branch",
+			tc: fsTest{
+				opts:    []Option{WithRepo("org", "repo")},
+				payload: []string{singleRepoWithHeadReponse},
+			},
+			expect: Manifest{
+				Branches: []LockedBranch{
+					{Org: "org", Repo: "repo", Branch: "main", Commit: "1111111111111111111111111111111111111111"},
+				},
+			},
+		}, {
+			description: "lock a named branch",
+			tc: fsTest{
+				opts:    []Option{WithRepo("org", "repo", "dev")},
+				payload: []string{singleRepoWithBranchHeadReponse},
+			},
+			expect: Manifest{
+				Branches: []LockedBranch{
+					{Org: "org", Repo: "repo", Branch: "dev", Commit: "2222222222222222222222222222222222222222"},
+				},
+			},
+		}, {
+			description: "lock the default branches of an org",
+			tc: fsTest{
+				opts:    []Option{WithOrg("org")},
+				payload: []string{twoReposWithHeadsResponse},
+			},
+			expect: Manifest{
+				Branches: []LockedBranch{
+					{Org: "org", Repo: ".github", Branch: "main", Commit: "3333333333333333333333333333333333333333"},
+					{Org: "org", Repo: ".go-template", Branch: "trunk", Commit: "4444444444444444444444444444444444444444"},
+				},
+			},
+		}, {
+			description: "branches are resolved once",
+			tc: fsTest{
+				opts: []Option{WithOrg("org"), WithRepo("org", ".github")},
+				payload: []string{
+					twoReposWithHeadsResponse,
+					strings.ReplaceAll(singleRepoWithHeadReponse, "org/repo", "org/.github"),
+				},
+			},
+			expect: Manifest{
+				Branches: []LockedBranch{
+					{Org: "org", Repo: ".github", Branch: "main", Commit: "3333333333333333333333333333333333333333"},
+					{Org: "org", Repo: ".go-template", Branch: "trunk", Commit: "4444444444444444444444444444444444444444"},
+				},
+			},
+		}, {
+			description: "lock discovered branches once they are listed",
+			tc: fsTest{
+				opts:    []Option{WithBranches("org", "repo", "release/*"), WithThresholdInKB(0)},
+				payload: []string{singleRepoWithHeadReponse, branchesWithHeadsResponse, baseDirectoryResponse},
+			},
+			open: []string{"org/repo/git/release/1.0"},
+			expect: Manifest{
+				Branches: []LockedBranch{
+					{Org: "org", Repo: "repo", Branch: "release/1.0", Commit: "5555555555555555555555555555555555555555"},
+				},
+			},
+		}, {
+			description: "a lock overrides the head of a branch",
+			tc: fsTest{
+				opts: []Option{
+					WithRepo("org", "repo"),
+					WithLock(Manifest{
+						Branches: []LockedBranch{
+							{Org: "org", Repo: "repo", Branch: "main", Commit: "6666666666666666666666666666666666666666"},
+							{Org: "org", Repo: "other", Branch: "main", Commit: "7777777777777777777777777777777777777777"},
+						},
+					}),
+				},
+				payload: []string{singleRepoWithHeadReponse},
+			},
+			expect: Manifest{
+				Branches: []LockedBranch{
+					{Org: "org", Repo: "repo", Branch: "main", Commit: "6666666666666666666666666666666666666666"},
+				},
+			},
+		}, {
+			description: "lock a branch at a point in time",
+			tc: fsTest{
+				opts:    []Option{WithRepo("org", "repo"), WithAsOf(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))},
+				payload: []string{singleRepoWithHeadReponse, historyResponse},
+			},
+			expect: Manifest{
+				Branches: []LockedBranch{
+					{Org: "org", Repo: "repo", Branch: "main", Commit: "8888888888888888888888888888888888888888"},
+				},
+			},
+		}, {
+			description: "an error connecting",
+			tc: fsTest{
+				opts:    []Option{WithRepo("org", "repo")},
+				payload: []string{invalidJsonResponse},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			gfs, _, _ := newTestFS(t, tc.tc)
+			require.NotNil(gfs)
+
+			for _, path := range tc.open {
+				_, err := fs.Stat(gfs, path)
+				require.NoError(err)
+			}
+
+			got, err := gfs.Lock()
+			if tc.expectErr {
+				assert.Error(err)
+				return
+			}
+
+			require.NoError(err)
+			assert.Equal(tc.expect, got)
+		})
+	}
+}
+
+func TestBranchHeadQuery(t *testing.T) {
+	tests := []struct {
+		description string
+		opts        []Option
+		payload     string
+		contains    string
+		notContains string
+	}{
+		{
+			description: "the default branch comes with the repo",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     singleRepoWithHeadReponse,
+			notContains: "qualifiedName",
+		}, {
+			description: "a named branch is asked for",
+			opts:        []Option{WithRepo("org", "repo", "dev")},
+			payload:     singleRepoWithBranchHeadReponse,
+			contains:    `"branch":"refs/heads/dev"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			gfs, _, bodies := newTestFS(t, fsTest{
+				opts:    tc.opts,
+				payload: []string{tc.payload},
+			})
+
+			_, err := gfs.Lock()
+			require.NoError(err)
+			require.Len(*bodies, 1)
+			if len(tc.contains) > 0 {
+				assert.Contains((*bodies)[0], tc.contains)
+			}
+			if len(tc.notContains) > 0 {
+				assert.NotContains((*bodies)[0], tc.notContains)
+			}
+		})
+	}
+}
+
+func TestLockedReads(t *testing.T) {
+	lock := Manifest{
+		Branches: []LockedBranch{
+			{Org: "org", Repo: "repo", Branch: "main", Commit: "6666666666666666666666666666666666666666"},
+		},
+	}
+
+	// Make sure the manifest survives being serialized.
+	buf, err := json.Marshal(lock)
+	require.NoError(t, err)
+	var restored Manifest
+	require.NoError(t, json.Unmarshal(buf, &restored))
+
+	tests := []fsTest{
+		{
+			description: "read a file at the head of the branch",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoWithHeadReponse, baseDirectoryResponse, readmeResponse},
+			contents: map[string]string{
+				"org/repo/git/main/README.md": readmeResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"GET /org/repo/1111111111111111111111111111111111111111/README.md",
+			},
+		}, {
+			description: "read a file at the locked commit",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0), WithLock(restored)},
+			payload:     []string{singleRepoWithHeadReponse, baseDirectoryResponse, readmeResponse},
+			contents: map[string]string{
+				"org/repo/git/main/README.md": readmeResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"GET /org/repo/6666666666666666666666666666666666666666/README.md",
+			},
+		}, {
+			description: "read the locked commit all at once",
+			opts:        []Option{WithRepo("org", "repo"), WithLock(restored)},
+			payload:     []string{singleRepoWithHeadReponse, commitTarballResponse, fullRepoTarball},
+			expect:      []string{"org/repo/git/main/a"},
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+var singleRepoWithHeadReponse = `{
+  "data": {
+    "repository": {
+      "diskUsage": 18,
+      "isArchived": false,
+      "isDisabled": false,
+      "nameWithOwner": "org/repo",
+      "defaultBranchRef": {
+        "name": "main",
+        "target": {
+          "oid": "1111111111111111111111111111111111111111"
+        }
+      },
+      "releases": {
+        "totalCount": 0
+      }
+    }
+  }
+}`
+
+var singleRepoWithBranchHeadReponse = `{
+  "data": {
+    "repository": {
+      "diskUsage": 18,
+      "isArchived": false,
+      "isDisabled": false,
+      "nameWithOwner": "org/repo",
+      "defaultBranchRef": {
+        "name": "main",
+        "target": {
+          "oid": "1111111111111111111111111111111111111111"
+        }
+      },
+      "releases": {
+        "totalCount": 0
+      }
+    },
+    "branch": {
+      "ref": {
+        "target": {
+          "oid": "2222222222222222222222222222222222222222"
+        }
+      }
+    }
+  }
+}`
+
+var twoReposWithHeadsResponse = `{
+  "data": {
+    "repositoryOwner": {
+      "repositories": {
+        "edges": [
+          {
+            "node": {
+              "name": ".github",
+              "diskUsage": 77,
+              "isArchived": false,
+              "isDisabled": false,
+              "nameWithOwner": "org/.github",
+              "defaultBranchRef": {
+                "name": "main",
+                "target": {
+                  "oid": "3333333333333333333333333333333333333333"
+                }
+              },
+              "releases": {
+                "totalCount": 0
+              }
+            }
+          },
+          {
+            "node": {
+              "name": ".go-template",
+              "diskUsage": 43,
+              "isArchived": false,
+              "isDisabled": false,
+              "nameWithOwner": "org/.go-template",
+              "defaultBranchRef": {
+                "name": "trunk",
+                "target": {
+                  "oid": "4444444444444444444444444444444444444444"
+                }
+              },
+              "releases": {
+                "totalCount": 0
+              }
+            }
+          }
+        ],
+        "pageInfo": {
+          "endCursor": "Y3Vyc29yOnYyOpKsLmdvLXRlbXBsYXRlzgu16xQ=",
+          "hasNextPage": false
+        }
+      }
+    }
+  }
+}`
+
+var branchesWithHeadsResponse = `{
+  "data": {
+    "repository": {
+      "refs": {
+        "edges": [
+          {
+            "node": {
+              "name": "main",
+              "target": {
+                "oid": "1111111111111111111111111111111111111111"
+              }
+            }
+          },
+          {
+            "node": {
+              "name": "release/1.0",
+              "target": {
+                "oid": "5555555555555555555555555555555555555555"
+              }
+            }
+          }
+        ],
+        "pageInfo": {
+          "endCursor": "Mg",
+          "hasNextPage": false
+        }
+      }
+    }
+  }
+}`
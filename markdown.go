@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field is a single key and value in the front matter of a markdown file.
+type field struct {
+	key   string
+	value any
+}
+
+// frontMatter is an ordered list of fields rendered as the yaml front matter
+// of a markdown file.
+type frontMatter []field
+
+// String renders the front matter including the leading and trailing "---"
+// lines.  Strings are double quoted so any text is valid yaml, and nil or
+// zero times are left out.
+func (fm frontMatter) String() string {
+	var b strings.Builder
+
+	b.WriteString("---\n")
+	for _, f := range fm {
+		var val string
+		switch v := f.value.(type) {
+		case string:
+			val = strconv.Quote(v)
+		case int:
+			val = strconv.Itoa(v)
+		case bool:
+			val = strconv.FormatBool(v)
+		case []string:
+			quoted := make([]string, 0, len(v))
+			for _, s := range v {
+				quoted = append(quoted, strconv.Quote(s))
+			}
+			val = "[" + strings.Join(quoted, ", ") + "]"
+		case time.Time:
+			if v.IsZero() {
+				continue
+			}
+			val = v.UTC().Format(time.RFC3339)
+		case *time.Time:
+			if v == nil || v.IsZero() {
+				continue
+			}
+			val = v.UTC().Format(time.RFC3339)
+		default:
+			continue
+		}
+		b.WriteString(f.key)
+		b.WriteString(": ")
+		b.WriteString(val)
+		b.WriteString("\n")
+	}
+	b.WriteString("---\n")
+
+	return b.String()
+}
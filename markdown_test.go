@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrontMatter(t *testing.T) {
+	when := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description string
+		fm          frontMatter
+		expect      string
+	}{
+		{
+			description: "empty test",
+			expect:      "---\n---\n",
+		}, {
+			description: "all the types",
+			fm: frontMatter{
+				{key: "title", value: "Say \"hi\": now"},
+				{key: "number", value: 12},
+				{key: "answered", value: true},
+				{key: "labels", value: []string{"bug", "p1"}},
+				{key: "assignees", value: []string{}},
+				{key: "createdAt", value: when},
+				{key: "closedAt", value: &when},
+			},
+			expect: "---\n" +
+				"title: \"Say \\\"hi\\\": now\"\n" +
+				"number: 12\n" +
+				"answered: true\n" +
+				"labels: [\"bug\", \"p1\"]\n" +
+				"assignees: []\n" +
+				"createdAt: 2023-05-01T10:00:00Z\n" +
+				"closedAt: 2023-05-01T10:00:00Z\n" +
+				"---\n",
+		}, {
+			description: "missing values are left out",
+			fm: frontMatter{
+				{key: "createdAt", value: time.Time{}},
+				{key: "closedAt", value: (*time.Time)(nil)},
+				{key: "unknown", value: 1.5},
+			},
+			expect: "---\n---\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expect, tc.fm.String())
+		})
+	}
+}
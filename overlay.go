@@ -0,0 +1,315 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	patchFrom       = "From 0000000000000000000000000000000000000000 Mon Sep 17 00:00:00 2001"
+	patchDateLayout = "Mon, 2 Jan 2006 15:04:05 -0700"
+	patchSignature  = "-- \ngithubfs\n"
+	patchGraphWidth = 50
+)
+
+// ensure the Overlay matches the interface
+var _ fs.FS = (*Overlay)(nil)
+
+// Overlay is a copy-on-write layer over a filesystem.  Files in the branches
+// of the filesystem can be written, removed and renamed, and reads return the
+// changes merged with the branches.  The changes are never sent to github,
+// instead they are made into a patch to review or apply.
+type Overlay struct {
+	gfs    *FS
+	staged staging
+}
+
+// PatchHeader describes the commit a patch from FormatPatch() is made as.
+type PatchHeader struct {
+	// Author is the author of the commit like "Mona Lisa <mona@example.com>".
+	Author string
+
+	// Date is when the commit was made.  The current time is used if it is
+	// not set.
+	Date time.Time
+
+	// Message is the commit message.  The first line is the subject.
+	Message string
+}
+
+// diffStat is the number of lines changed in a file of a patch.
+type diffStat struct {
+	path string
+	adds int
+	dels int
+}
+
+// NewOverlay creates an overlay over the filesystem.
+func NewOverlay(gfs *FS) *Overlay {
+	return &Overlay{
+		gfs: gfs,
+	}
+}
+
+// Open opens the named file.  Files written to the overlay are returned in
+// place of the files of the filesystem, and removed files don't exist.
+func (o *Overlay) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, fmt.Errorf("open %s %w", name, fs.ErrInvalid)
+	}
+
+	data, staged, removed := o.staged.file(name)
+	if staged {
+		info := fileInfo{
+			name: path.Base(name),
+			size: int64(len(data)),
+			mode: fs.FileMode(0644),
+		}
+		return newFileHandle(info, data), nil
+	}
+	if removed {
+		return nil, fmt.Errorf("open %s is removed %w", name, fs.ErrNotExist)
+	}
+
+	added, gone := o.staged.entries(name)
+
+	f, err := o.gfs.Open(name)
+	if err != nil {
+		// Directories only made by the files written to them.
+		if errors.Is(err, fs.ErrNotExist) && len(added) > 0 {
+			info := &fileInfo{
+				name: path.Base(name),
+				size: 4096,
+				mode: fs.ModeDir | 0755,
+			}
+			return &dirHandle{info: info, entries: mergeEntries(nil, added, gone)}, nil
+		}
+		return nil, err
+	}
+
+	dh, ok := f.(*dirHandle)
+	if !ok || (len(added) == 0 && len(gone) == 0) {
+		return f, nil
+	}
+
+	return &dirHandle{info: dh.info, entries: mergeEntries(dh.entries, added, gone)}, nil
+}
+
+// mergeEntries merges the entries of a directory with the files and
+// directories written to it and the files removed from it.
+func mergeEntries(entries []fs.DirEntry, added map[string]*fileInfo, gone map[string]bool) []fs.DirEntry {
+	merged := make([]fs.DirEntry, 0, len(entries)+len(added))
+	for _, entry := range entries {
+		name := entry.Name()
+		if info, found := added[name]; found {
+			if !info.IsDir() || !entry.IsDir() {
+				merged = append(merged, &dirEntry{info: info})
+			} else {
+				merged = append(merged, entry)
+			}
+			delete(added, name)
+			continue
+		}
+		if gone[name] {
+			continue
+		}
+		merged = append(merged, entry)
+	}
+
+	for _, name := range sortedKeys(added) {
+		merged = append(merged, &dirEntry{info: added[name]})
+	}
+
+	return merged
+}
+
+// WriteFile writes the data to the named file in the overlay.  The path must
+// be in a branch like org/repo/git/main/config.yml.
+func (o *Overlay) WriteFile(name string, data []byte) error {
+	if err := o.staged.write(o.gfs, name, data); err != nil {
+		return fmt.Errorf("write %s %w", name, err)
+	}
+	return nil
+}
+
+// Remove removes the named file from the overlay.  The path must be a file in
+// a branch like org/repo/git/main/config.yml, or a file written to the
+// overlay.
+func (o *Overlay) Remove(name string) error {
+	if err := o.staged.remove(o.gfs, name); err != nil {
+		return fmt.Errorf("remove %s %w", name, err)
+	}
+	return nil
+}
+
+// Rename moves the file from the old name to the new name in the overlay.
+// Both names must be in the same branch.  A file at the new name is replaced.
+func (o *Overlay) Rename(oldname, newname string) error {
+	if err := o.staged.rename(o.gfs, oldname, newname); err != nil {
+		return fmt.Errorf("rename %s %w", oldname, err)
+	}
+	return nil
+}
+
+// Branches returns the paths of the branches with changes, like
+// org/repo/git/main, in order.
+func (o *Overlay) Branches() []string {
+	return o.staged.branches()
+}
+
+// Diff returns the unified diff of the changes to the branch at the path,
+// like org/repo/git/main, in the form used by git diff.  Renamed files are
+// shown as removed and added.
+func (o *Overlay) Diff(name string) (string, error) {
+	patch, _, err := o.diff(name)
+	if err != nil {
+		return "", fmt.Errorf("diff %s %w", name, err)
+	}
+	return patch, nil
+}
+
+// FormatPatch returns the changes to the branch at the path, like
+// org/repo/git/main, as a patch in the form made by git format-patch that can
+// be applied with git am.
+func (o *Overlay) FormatPatch(name string, h PatchHeader) (string, error) {
+	subject, body, _ := strings.Cut(strings.TrimSpace(h.Message), "\n")
+	if len(strings.TrimSpace(subject)) == 0 || len(h.Author) == 0 {
+		return "", fmt.Errorf("format patch %s needs an author and message %w", name, fs.ErrInvalid)
+	}
+
+	patch, stats, err := o.diff(name)
+	if err != nil {
+		return "", fmt.Errorf("format patch %s %w", name, err)
+	}
+
+	date := h.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	var b strings.Builder
+	b.WriteString(patchFrom + "\n")
+	fmt.Fprintf(&b, "From: %s\n", h.Author)
+	fmt.Fprintf(&b, "Date: %s\n", date.Format(patchDateLayout))
+	fmt.Fprintf(&b, "Subject: [PATCH] %s\n\n", strings.TrimSpace(subject))
+	if body = strings.TrimSpace(body); len(body) > 0 {
+		b.WriteString(body + "\n\n")
+	}
+	b.WriteString("---\n")
+	writeDiffStat(&b, stats)
+	b.WriteString("\n")
+	b.WriteString(patch)
+	b.WriteString(patchSignature)
+
+	return b.String(), nil
+}
+
+// diff makes the unified diff of the changes to the branch and counts the
+// lines changed in each file.
+func (o *Overlay) diff(name string) (string, []diffStat, error) {
+	key := path.Clean(name)
+	_, additions, deletions, found := o.staged.get(key)
+	if !found {
+		return "", nil, fmt.Errorf("nothing is changed %w", fs.ErrInvalid)
+	}
+
+	paths := append(sortedKeys(additions), deletions...)
+	sort.Strings(paths)
+
+	var b strings.Builder
+	var stats []diffStat
+	for _, p := range paths {
+		fd := fileDiff{path: p}
+
+		old, err := fs.ReadFile(o.gfs, key+"/"+p)
+		switch {
+		case err == nil:
+			fd.old = old
+			if info, err := fs.Stat(o.gfs, key+"/"+p); err == nil {
+				fd.exec = info.Mode()&0111 != 0
+			}
+		case errors.Is(err, fs.ErrNotExist):
+			fd.created = true
+		default:
+			return "", nil, err
+		}
+
+		if data, found := additions[p]; found {
+			fd.new = data
+		} else {
+			fd.deleted = true
+		}
+
+		before := b.Len()
+		adds, dels := fd.write(&b)
+		if b.Len() > before {
+			stats = append(stats, diffStat{path: p, adds: adds, dels: dels})
+		}
+	}
+
+	return b.String(), stats, nil
+}
+
+// writeDiffStat writes the summary of the lines changed in each file like
+// git diff --stat.  The graphs are scaled down if they are too wide.
+func writeDiffStat(b *strings.Builder, stats []diffStat) {
+	var width, most, adds, dels int
+	for _, s := range stats {
+		if len(s.path) > width {
+			width = len(s.path)
+		}
+		if s.adds+s.dels > most {
+			most = s.adds + s.dels
+		}
+		adds += s.adds
+		dels += s.dels
+	}
+	digits := len(fmt.Sprint(most))
+
+	for _, s := range stats {
+		plus, minus := s.adds, s.dels
+		if most > patchGraphWidth {
+			plus = scaleGraph(plus, most)
+			minus = scaleGraph(minus, most)
+		}
+		graph := strings.Repeat("+", plus) + strings.Repeat("-", minus)
+		fmt.Fprintf(b, " %-*s | %*d %s\n", width, s.path, digits, s.adds+s.dels, graph)
+	}
+
+	fmt.Fprintf(b, " %d %s changed", len(stats), plural(len(stats), "file", "files"))
+	if adds > 0 || dels == 0 {
+		fmt.Fprintf(b, ", %d %s(+)", adds, plural(adds, "insertion", "insertions"))
+	}
+	if dels > 0 || adds == 0 {
+		fmt.Fprintf(b, ", %d %s(-)", dels, plural(dels, "deletion", "deletions"))
+	}
+	b.WriteString("\n")
+}
+
+// scaleGraph scales the number of lines to the width of the graph, keeping
+// at least one mark for any change.
+func scaleGraph(n, most int) int {
+	if n == 0 {
+		return 0
+	}
+	if scaled := n * patchGraphWidth / most; scaled > 0 {
+		return scaled
+	}
+	return 1
+}
+
+// plural picks the singular or plural word for the count.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
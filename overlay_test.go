@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlay(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, requests, _ := newTestFS(t, fsTest{
+		opts:    []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+		payload: []string{singleRepoWithHeadReponse, baseDirectoryResponse, readmeResponse, executableResponse},
+	})
+	o := NewOverlay(gfs)
+
+	require.NoError(o.WriteFile("org/repo/git/main/config/app.yml", []byte("on: true\n")))
+	require.NoError(o.WriteFile("org/repo/git/main/README.md", []byte("Readme.md contents\nMore\n")))
+	require.NoError(o.Remove("org/repo/git/main/executable"))
+	assert.Equal([]string{"org/repo/git/main"}, o.Branches())
+
+	// Reads are merged with the branch.
+	got, err := fs.ReadFile(o, "org/repo/git/main/config/app.yml")
+	require.NoError(err)
+	assert.Equal("on: true\n", string(got))
+
+	_, err = o.Open("org/repo/git/main/executable")
+	assert.ErrorIs(err, fs.ErrNotExist)
+
+	entries, err := fs.ReadDir(o, "org/repo/git/main")
+	require.NoError(err)
+	assert.Equal([]string{".github", ".gitignore", "README.md", "config"}, entryNames(entries))
+
+	entries, err = fs.ReadDir(o, "org/repo/git/main/config")
+	require.NoError(err)
+	assert.Equal([]string{"app.yml"}, entryNames(entries))
+
+	info, err := fs.Stat(o, "org/repo/git/main/README.md")
+	require.NoError(err)
+	assert.Equal(int64(24), info.Size())
+
+	diff, err := o.Diff("org/repo/git/main")
+	require.NoError(err)
+	assert.Equal(overlayDiff, diff)
+
+	patch, err := o.FormatPatch("org/repo/git/main", PatchHeader{
+		Author:  "Mona Lisa <mona@example.com>",
+		Date:    time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC),
+		Message: "Turn it on\n\nThe config turns it on.",
+	})
+	require.NoError(err)
+	assert.Equal(overlayPatch, patch)
+
+	// Nothing is written to github.
+	assert.Equal([]string{
+		"POST /",
+		"POST /",
+		"GET /org/repo/1111111111111111111111111111111111111111/README.md",
+		"GET /org/repo/1111111111111111111111111111111111111111/executable",
+	}, *requests)
+
+	// The filesystem itself is unchanged.
+	got, err = fs.ReadFile(gfs, "org/repo/git/main/README.md")
+	require.NoError(err)
+	assert.Equal(readmeResponse, string(got))
+}
+
+func TestFormatPatchScalesDiffStat(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, _, _ := newTestFS(t, fsTest{
+		opts:    []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+		payload: []string{singleRepoWithHeadReponse, baseDirectoryResponse, executableResponse},
+	})
+	o := NewOverlay(gfs)
+
+	require.NoError(o.WriteFile("org/repo/git/main/big.txt", []byte(strings.Repeat("line\n", 100))))
+	require.NoError(o.WriteFile("org/repo/git/main/small.txt", []byte("line\n")))
+	require.NoError(o.Remove("org/repo/git/main/executable"))
+
+	patch, err := o.FormatPatch("org/repo/git/main", PatchHeader{
+		Author:  "Mona Lisa <mona@example.com>",
+		Date:    time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC),
+		Message: "Add a big file",
+	})
+	require.NoError(err)
+
+	// The largest change fills the graph and the others keep at least one
+	// mark.
+	_, stat, _ := strings.Cut(patch, "---\n")
+	stat, _, _ = strings.Cut(stat, "\n\n")
+	assert.Equal(" big.txt    | 100 "+strings.Repeat("+", patchGraphWidth)+"\n"+
+		" executable |   2 -\n"+
+		" small.txt  |   1 +\n"+
+		" 3 files changed, 101 insertions(+), 2 deletions(-)", stat)
+}
+
+func TestOverlayErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	gfs, _, _ := newTestFS(t, fsTest{
+		opts:    []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+		payload: []string{singleRepoWithHeadReponse, baseDirectoryResponse},
+	})
+	o := NewOverlay(gfs)
+
+	_, err := o.Open("/org")
+	assert.ErrorIs(err, fs.ErrInvalid)
+
+	_, err = o.Diff("org/repo/git/main")
+	assert.ErrorIs(err, fs.ErrInvalid)
+
+	assert.ErrorIs(o.WriteFile("org/repo/git", nil), fs.ErrNotExist)
+	assert.ErrorIs(o.Remove("org/repo/git/main/missing.yml"), fs.ErrNotExist)
+
+	assert.NoError(o.Remove("org/repo/git/main/README.md"))
+	assert.ErrorIs(o.Rename("org/repo/git/main/README.md", "org/repo/git/main/docs.md"), fs.ErrNotExist)
+
+	_, err = o.FormatPatch("org/repo/git/main", PatchHeader{Message: "No author"})
+	assert.ErrorIs(err, fs.ErrInvalid)
+}
+
+// entryNames returns the names of the directory entries.
+func entryNames(entries []fs.DirEntry) []string {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+var executableResponse = "#!/bin/sh\necho hi\n"
+
+var overlayDiff = `diff --git a/README.md b/README.md
+index 99be040..af52ff6 100644
+--- a/README.md
++++ b/README.md
+@@ -1 +1,2 @@
+-Readme.md contents
+\ No newline at end of file
++Readme.md contents
++More
+diff --git a/config/app.yml b/config/app.yml
+new file mode 100644
+index 0000000..a1b975b
+--- /dev/null
++++ b/config/app.yml
+@@ -0,0 +1 @@
++on: true
+diff --git a/executable b/executable
+deleted file mode 100755
+index 4163036..0000000
+--- a/executable
++++ /dev/null
+@@ -1,2 +0,0 @@
+-#!/bin/sh
+-echo hi
+`
+
+var overlayPatch = `From 0000000000000000000000000000000000000000 Mon Sep 17 00:00:00 2001
+From: Mona Lisa <mona@example.com>
+Date: Thu, 1 Jun 2023 10:00:00 +0000
+Subject: [PATCH] Turn it on
+
+The config turns it on.
+
+---
+ README.md      | 3 ++-
+ config/app.yml | 1 +
+ executable     | 2 --
+ 3 files changed, 3 insertions(+), 3 deletions(-)
+
+` + overlayDiff + "-- \ngithubfs\n"
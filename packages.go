@@ -0,0 +1,378 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	fileNamePackageInfo     = "metadata.json"
+	fileNameImageManifest   = "manifest.json"
+	fileNameImageConfig     = "config.json"
+	packageTypeContainer    = "container"
+	mediaTypeImageManifests = "application/vnd.oci.image.manifest.v1+json, " +
+		"application/vnd.oci.image.index.v1+json, " +
+		"application/vnd.docker.distribution.manifest.v2+json, " +
+		"application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+// packageTypes are the types of packages included in the packages directory.
+var packageTypes = []string{packageTypeContainer, "npm"}
+
+// errNoChallenge is returned when a registry requires authentication, but
+// doesn't say how.
+var errNoChallenge = errors.New("registry authentication challenge not supported")
+
+// errUntrustedRealm is returned when a registry challenge sends the token
+// request to a different host.
+var errUntrustedRealm = errors.New("registry authentication realm not trusted")
+
+// WithPackages includes the packages directory in each repo.  The packages
+// are listed for the owner of the repo, which needs a token with the
+// read:packages scope, so they are only included when asked for.
+func WithPackages() Option {
+	return func(gfs *FS) {
+		gfs.packages = true
+	}
+}
+
+// WithContainerRegistry provides a way to set the OCI registry the container
+// packages are read from.  The default is ghcr.io, or containers.<host> for
+// github enterprise.
+//
+// The registry isn't read with the http client from WithHttpClient() since
+// that client carries the github credentials.  An http client with the
+// credentials for the registry may be provided, otherwise http.DefaultClient
+// is used.
+func WithContainerRegistry(baseURL string, c ...*http.Client) Option {
+	return func(gfs *FS) {
+		gfs.containerUrl = baseURL
+		if len(c) > 0 && c[0] != nil {
+			gfs.regClient = c[0]
+		}
+	}
+}
+
+// restPackage is a package as returned by the REST API.
+type restPackage struct {
+	Name        string `json:"name"`
+	PackageType string `json:"package_type"`
+	Repository  struct {
+		Name string `json:"name"`
+	} `json:"repository"`
+}
+
+// restPackageVersion is a package version as returned by the REST API.
+type restPackageVersion struct {
+	Name      string    `json:"name"`
+	Url       string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Metadata  struct {
+		Container struct {
+			Tags []string `json:"tags"`
+		} `json:"container"`
+	} `json:"metadata"`
+}
+
+// packageInfo is the metadata about a package version written to
+// metadata.json.
+type packageInfo struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Version   string    `json:"version"`
+	Tags      []string  `json:"tags,omitempty"`
+	Url       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// packagesFetcher returns the fetcher for the packages directory.  The REST
+// API has different paths for the packages of organizations and users.  A
+// package type the token can't list the packages of has no packages.
+func packagesFetcher(ownerType string) func(*FS, *dir) error {
+	owners := "orgs"
+	if ownerType == "User" {
+		owners = "users"
+	}
+
+	return func(gfs *FS, d *dir) error {
+		base := strings.Join([]string{gfs.restUrl, owners, d.org, "packages"}, "/")
+
+		for _, pkgType := range packageTypes {
+			pkgs, err := ownerPackages(gfs, base+"?package_type="+pkgType)
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+					continue
+				}
+				return err
+			}
+
+			for _, pkg := range pkgs {
+				// The packages are listed for the owner, so only keep the
+				// ones linked to this repo.
+				if pkg.Repository.Name != d.repo {
+					continue
+				}
+
+				versions := strings.Join([]string{base, pkgType, url.PathEscape(pkg.Name), "versions"}, "/")
+				d.mkdir(pkgType, notInPath()).
+					mkref(pkg.Name, notInPath(), withFetcher(packageVersionsFetcher(versions, pkgType, pkg.Name)))
+			}
+		}
+
+		return nil
+	}
+}
+
+// ownerPackages lists the packages at the url once per filesystem since every
+// repo of the owner shares the same list.
+func ownerPackages(gfs *FS, u string) ([]restPackage, error) {
+	gfs.packagesLock.Lock()
+	defer gfs.packagesLock.Unlock()
+
+	if pkgs, found := gfs.ownerPkgs[u]; found {
+		return pkgs, nil
+	}
+
+	var pkgs []restPackage
+	err := restPages(gfs, u, "", func(list []restPackage) error {
+		pkgs = append(pkgs, list...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gfs.ownerPkgs[u] = pkgs
+	return pkgs, nil
+}
+
+// packageVersionsFetcher returns the fetcher for the directory of a package
+// that makes each version into a directory.
+func packageVersionsFetcher(versions, pkgType, name string) func(*FS, *dir) error {
+	return func(gfs *FS, d *dir) error {
+		return restPages(gfs, versions, "", func(list []restPackageVersion) error {
+			for _, v := range list {
+				info := packageInfo{
+					Name:      name,
+					Type:      pkgType,
+					Version:   v.Name,
+					Tags:      v.Metadata.Container.Tags,
+					Url:       v.Url,
+					CreatedAt: v.CreatedAt,
+					UpdatedAt: v.UpdatedAt,
+				}
+
+				buf, err := json.MarshalIndent(info, "", "  ")
+				if err != nil {
+					return err
+				}
+
+				opts := []dirOpt{notInPath(), withDirModTime(v.UpdatedAt)}
+				if pkgType == packageTypeContainer {
+					opts = append(opts, withFetcher(imageFetcher(name, v.Name)))
+				}
+				vDir := d.newDir(v.Name, opts...)
+				vDir.addFile(fileNamePackageInfo, withContent(buf), withModTime(v.UpdatedAt))
+			}
+			return nil
+		})
+	}
+}
+
+// imageFetcher returns the fetcher for the directory of a container package
+// version that reads the OCI manifest and the image config from the registry.
+// Multi-platform images only have the image index as the manifest.
+func imageFetcher(name, digest string) func(*FS, *dir) error {
+	return func(gfs *FS, d *dir) error {
+		reg := registry{
+			gfs:  gfs,
+			base: strings.Join([]string{gfs.containerUrl, "v2", strings.ToLower(d.org), name}, "/"),
+		}
+
+		manifest, err := reg.get("manifests/"+digest, mediaTypeImageManifests)
+		if err != nil {
+			return err
+		}
+		d.addFile(fileNameImageManifest, withContent(manifest), withModTime(d.modTime))
+
+		var m struct {
+			Config struct {
+				Digest string `json:"digest"`
+			} `json:"config"`
+		}
+		if err = json.Unmarshal(manifest, &m); err != nil {
+			return err
+		}
+		if len(m.Config.Digest) == 0 {
+			return nil
+		}
+
+		config, err := reg.get("blobs/"+m.Config.Digest, "")
+		if err != nil {
+			return err
+		}
+		d.addFile(fileNameImageConfig, withContent(config), withModTime(d.modTime))
+
+		return nil
+	}
+}
+
+// registry reads from an image repository in an OCI registry.  Registries
+// that answer with a bearer token challenge are handled by fetching a token
+// from the realm in the challenge.  The registry is read with its own http
+// client so the github credentials aren't sent to it.
+type registry struct {
+	gfs   *FS
+	base  string
+	token string
+}
+
+// get fetches the path relative to the image repository.
+func (r *registry) get(path, accept string) ([]byte, error) {
+	resp, err := r.do(r.base+"/"+path, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized && len(r.token) == 0 {
+		if err = r.authenticate(resp.Header.Get("Www-Authenticate")); err != nil {
+			return nil, err
+		}
+		return r.get(path, accept)
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("http status code not 200: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// do makes the request with the token if there is one.
+func (r *registry) do(u, accept string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(accept) > 0 {
+		req.Header.Set("Accept", accept)
+	}
+	if len(r.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	return r.gfs.regClient.Do(req)
+}
+
+// authenticate fetches a token from the realm in the challenge.  Only a realm
+// on the same host as the registry is trusted.
+func (r *registry) authenticate(challenge string) error {
+	realm, params := parseChallenge(challenge)
+	if len(realm) == 0 {
+		return errNoChallenge
+	}
+
+	realmUrl, err := url.Parse(realm)
+	if err != nil {
+		return err
+	}
+	baseUrl, err := url.Parse(r.base)
+	if err != nil {
+		return err
+	}
+	if realmUrl.Host != baseUrl.Host {
+		return fmt.Errorf("%w: the realm %s isn't on the registry host", errUntrustedRealm, realm)
+	}
+
+	q := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if val, found := params[key]; found {
+			q.Set(key, val)
+		}
+	}
+
+	resp, err := r.do(realm+"?"+q.Encode(), "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("http status code not 200: %d", resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return err
+	}
+
+	r.token = token.Token
+	if len(r.token) == 0 {
+		r.token = token.AccessToken
+	}
+	if len(r.token) == 0 {
+		return errNoChallenge
+	}
+
+	return nil
+}
+
+// parseChallenge parses a bearer challenge like:
+//
+// Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:org/app:pull"
+//
+// into the realm and the rest of the parameters.
+func parseChallenge(challenge string) (string, map[string]string) {
+	const scheme = "bearer "
+	if len(challenge) < len(scheme) || !strings.EqualFold(challenge[:len(scheme)], scheme) {
+		return "", nil
+	}
+
+	params := make(map[string]string)
+	rest := strings.TrimSpace(challenge[len(scheme):])
+	for len(rest) > 0 {
+		eq := strings.Index(rest, "=")
+		if eq < 1 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = rest[eq+1:]
+
+		var val string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				break
+			}
+			val, rest = rest[1:end+1], rest[end+2:]
+		} else {
+			end := strings.Index(rest, ",")
+			if end < 0 {
+				end = len(rest)
+			}
+			val, rest = rest[:end], rest[end:]
+		}
+		params[key] = val
+		rest = strings.TrimLeft(rest, ", ")
+	}
+
+	realm := params["realm"]
+	delete(params, "realm")
+	return realm, params
+}
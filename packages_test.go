@@ -0,0 +1,386 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackages(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "fetch a container image",
+			opts:        []Option{WithRepo("org", "repo"), WithPackages()},
+			payload: []string{singleRepoReponse, containerPackagesResponse, npmPackagesResponse,
+				containerVersionsResponse, imageManifest, imageConfig},
+			expect:     []string{"org/repo/packages/npm"},
+			unexpected: []string{"org/repo/packages/container/other"},
+			contents: map[string]string{
+				"org/repo/packages/container/app/sha256:aaaa/config.json":   imageConfig,
+				"org/repo/packages/container/app/sha256:aaaa/manifest.json": imageManifest,
+				"org/repo/packages/container/app/sha256:aaaa/metadata.json": appMetadataJson,
+			},
+			requests: []string{
+				"POST /",
+				"GET /orgs/org/packages?package_type=container&per_page=100&page=1",
+				"GET /orgs/org/packages?package_type=npm&per_page=100&page=1",
+				"GET /orgs/org/packages/container/app/versions?per_page=100&page=1",
+				"GET /v2/org/app/manifests/sha256:aaaa",
+				"GET /v2/org/app/blobs/sha256:cccc",
+			},
+		}, {
+			description: "fetch an npm package owned by a user",
+			opts:        []Option{WithRepo("org", "repo"), WithPackages()},
+			payload:     []string{singleUserRepoReponse, emptyListResponse, npmPackagesResponse, npmVersionsResponse},
+			contents: map[string]string{
+				"org/repo/packages/npm/lib/1.2.3/metadata.json": libMetadataJson,
+			},
+			requests: []string{
+				"POST /",
+				"GET /users/org/packages?package_type=container&per_page=100&page=1",
+				"GET /users/org/packages?package_type=npm&per_page=100&page=1",
+				"GET /users/org/packages/npm/lib/versions?per_page=100&page=1",
+			},
+		}, {
+			description: "fetch the packages, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo"), WithPackages()},
+			payload:     []string{singleRepoReponse, invalidJsonResponse},
+			expect:      []string{"org/repo/packages/npm"},
+			expectErr:   true,
+		}, {
+			description: "fetch the package versions, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo"), WithPackages()},
+			payload:     []string{singleRepoReponse, emptyListResponse, npmPackagesResponse, invalidJsonResponse},
+			expect:      []string{"org/repo/packages/npm/lib/1.2.3"},
+			expectErr:   true,
+		}, {
+			description: "list the packages of the owner once",
+			opts:        []Option{WithRepo("org", "repo"), WithRepo("org", "other-repo"), WithPackages()},
+			payload: []string{singleRepoReponse, otherRepoReponse, containerPackagesResponse,
+				npmPackagesResponse},
+			expect: []string{"org/repo/packages/container", "org/other-repo/packages/container"},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"GET /orgs/org/packages?package_type=container&per_page=100&page=1",
+				"GET /orgs/org/packages?package_type=npm&per_page=100&page=1",
+			},
+		}, {
+			description: "packages the token can't list are empty",
+			opts:        []Option{WithRepo("org", "repo"), WithPackages()},
+			statusCode:  []int{0, 403, 403},
+			payload:     []string{singleRepoReponse, "{}", "{}"},
+			expect:      []string{"org/repo/packages"},
+			unexpected:  []string{"org/repo/packages/npm"},
+		}, {
+			description: "a package type the token can't list doesn't hide the others",
+			opts:        []Option{WithRepo("org", "repo"), WithPackages()},
+			statusCode:  []int{0, 403, 0},
+			payload:     []string{singleRepoReponse, "{}", npmPackagesResponse},
+			expect:      []string{"org/repo/packages/npm"},
+			unexpected:  []string{"org/repo/packages/container"},
+			requests: []string{
+				"POST /",
+				"GET /orgs/org/packages?package_type=container&per_page=100&page=1",
+				"GET /orgs/org/packages?package_type=npm&per_page=100&page=1",
+			},
+		}, {
+			description: "packages that can't be found are empty",
+			opts:        []Option{WithRepo("org", "repo"), WithPackages()},
+			statusCode:  []int{0, 404, 404},
+			payload:     []string{singleRepoReponse, "{}", "{}"},
+			expect:      []string{"org/repo/packages"},
+			unexpected:  []string{"org/repo/packages/npm"},
+		}, {
+			description: "packages aren't included unless asked for",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse},
+			unexpected:  []string{"org/repo/packages"},
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+// githubAuth adds the github credentials to each request like an oauth2
+// transport does.
+type githubAuth struct{}
+
+func (githubAuth) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer github")
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// newTestRegistry returns a registry that serves the image manifest and config
+// of org/app once a token is fetched from the realm.
+func newTestRegistry(t *testing.T, realm func(string) string) *httptest.Server {
+	var url string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEqual(t, "Bearer github", r.Header.Get("Authorization"))
+
+		switch {
+		case r.URL.Path == "/token":
+			assert.Empty(t, r.Header.Get("Authorization"))
+			assert.Equal(t, "registry", r.URL.Query().Get("service"))
+			assert.Equal(t, "repository:org/app:pull", r.URL.Query().Get("scope"))
+			fmt.Fprint(w, `{"token":"secret"}`)
+		case r.Header.Get("Authorization") != "Bearer secret":
+			w.Header().Set("Www-Authenticate",
+				`Bearer realm="`+realm(url)+`/token",service="registry",scope="repository:org/app:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/org/app/manifests/sha256:aaaa":
+			fmt.Fprint(w, imageManifest)
+		case r.URL.Path == "/v2/org/app/blobs/sha256:cccc":
+			fmt.Fprint(w, imageConfig)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	url = server.URL
+
+	return server
+}
+
+func TestRegistry(t *testing.T) {
+	server := newTestRegistry(t, func(url string) string { return url })
+
+	reg := registry{
+		gfs:  New(),
+		base: server.URL + "/v2/org/app",
+	}
+	got, err := reg.get("manifests/sha256:aaaa", mediaTypeImageManifests)
+	require.NoError(t, err)
+	assert.Equal(t, imageManifest, string(got))
+	assert.Equal(t, "secret", reg.token)
+}
+
+func TestRegistryUntrustedRealm(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("the token was requested from another host: %s", r.URL)
+	}))
+	defer other.Close()
+
+	server := newTestRegistry(t, func(string) string { return other.URL })
+
+	reg := registry{
+		gfs:  New(),
+		base: server.URL + "/v2/org/app",
+	}
+	got, err := reg.get("manifests/sha256:aaaa", mediaTypeImageManifests)
+	assert.ErrorIs(t, err, errUntrustedRealm)
+	assert.Nil(t, got)
+}
+
+func TestWithContainerRegistry(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/":
+			fmt.Fprint(w, singleRepoReponse)
+		case r.URL.Query().Get("package_type") == packageTypeContainer:
+			fmt.Fprint(w, containerPackagesResponse)
+		case r.URL.Path == "/orgs/org/packages/container/app/versions":
+			fmt.Fprint(w, containerVersionsResponse)
+		default:
+			fmt.Fprint(w, emptyListResponse)
+		}
+	}))
+	defer gh.Close()
+
+	server := newTestRegistry(t, func(url string) string { return url })
+
+	gfs := New(
+		WithHttpClient(&http.Client{Transport: githubAuth{}}),
+		WithRepo("org", "repo"),
+		WithPackages(),
+		withTestURL(gh.URL),
+		WithContainerRegistry(server.URL),
+	)
+
+	contents := map[string]string{
+		fileNameImageManifest: imageManifest,
+		fileNameImageConfig:   imageConfig,
+	}
+	for name, want := range contents {
+		got, err := fs.ReadFile(gfs, "org/repo/packages/container/app/sha256:aaaa/"+name)
+		require.NoError(err)
+		assert.Equal(want, string(got))
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	tests := []struct {
+		description string
+		challenge   string
+		realm       string
+		params      map[string]string
+	}{
+		{
+			description: "a bearer challenge",
+			challenge:   `Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:org/app:pull,push"`,
+			realm:       "https://ghcr.io/token",
+			params: map[string]string{
+				"service": "ghcr.io",
+				"scope":   "repository:org/app:pull,push",
+			},
+		}, {
+			description: "unquoted values",
+			challenge:   `bearer realm=https://example.com/token, service=example`,
+			realm:       "https://example.com/token",
+			params: map[string]string{
+				"service": "example",
+			},
+		}, {
+			description: "a basic challenge",
+			challenge:   `Basic realm="registry"`,
+		}, {
+			description: "no challenge",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			realm, params := parseChallenge(tc.challenge)
+			assert.Equal(tc.realm, realm)
+			if tc.params != nil {
+				assert.Equal(tc.params, params)
+			}
+		})
+	}
+}
+
+var singleUserRepoReponse = `{
+  "data": {
+    "repository": {
+      "diskUsage": 18,
+      "isArchived": false,
+      "isDisabled": false,
+      "nameWithOwner": "org/repo",
+      "owner": {
+        "__typename": "User"
+      },
+      "defaultBranchRef": {
+        "name": "main"
+      },
+      "releases": {
+        "totalCount": 0
+      }
+    }
+  }
+}`
+
+var otherRepoReponse = `{
+  "data": {
+    "repository": {
+      "diskUsage": 18,
+      "isArchived": false,
+      "isDisabled": false,
+      "nameWithOwner": "org/other-repo",
+      "defaultBranchRef": {
+        "name": "main"
+      },
+      "releases": {
+        "totalCount": 0
+      }
+    }
+  }
+}`
+
+var emptyListResponse = `[]`
+
+var containerPackagesResponse = `[
+  {
+    "name": "app",
+    "package_type": "container",
+    "repository": { "name": "repo" }
+  },
+  {
+    "name": "other",
+    "package_type": "container",
+    "repository": { "name": "other-repo" }
+  }
+]`
+
+var npmPackagesResponse = `[
+  {
+    "name": "lib",
+    "package_type": "npm",
+    "repository": { "name": "repo" }
+  }
+]`
+
+var containerVersionsResponse = `[
+  {
+    "name": "sha256:aaaa",
+    "html_url": "https://github.com/orgs/org/packages/container/app/1",
+    "created_at": "2023-05-01T10:00:00Z",
+    "updated_at": "2023-05-02T10:00:00Z",
+    "metadata": {
+      "package_type": "container",
+      "container": {
+        "tags": ["latest", "v1.0.0"]
+      }
+    }
+  }
+]`
+
+var npmVersionsResponse = `[
+  {
+    "name": "1.2.3",
+    "html_url": "https://github.com/org/repo/packages/2",
+    "created_at": "2023-05-01T10:00:00Z",
+    "updated_at": "2023-05-01T10:00:00Z",
+    "metadata": {
+      "package_type": "npm"
+    }
+  }
+]`
+
+var imageManifest = `{
+  "schemaVersion": 2,
+  "mediaType": "application/vnd.oci.image.manifest.v1+json",
+  "config": {
+    "mediaType": "application/vnd.oci.image.config.v1+json",
+    "digest": "sha256:cccc",
+    "size": 42
+  },
+  "layers": []
+}`
+
+var imageConfig = `{"architecture":"amd64","os":"linux"}`
+
+var appMetadataJson = `{
+  "name": "app",
+  "type": "container",
+  "version": "sha256:aaaa",
+  "tags": [
+    "latest",
+    "v1.0.0"
+  ],
+  "url": "https://github.com/orgs/org/packages/container/app/1",
+  "createdAt": "2023-05-01T10:00:00Z",
+  "updatedAt": "2023-05-02T10:00:00Z"
+}`
+
+var libMetadataJson = `{
+  "name": "lib",
+  "type": "npm",
+  "version": "1.2.3",
+  "url": "https://github.com/org/repo/packages/2",
+  "createdAt": "2023-05-01T10:00:00Z",
+  "updatedAt": "2023-05-01T10:00:00Z"
+}`
@@ -0,0 +1,389 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ProposeOptions configures the pull requests made by ProposeChanges().
+//
+// The branch, title and body are text/template templates executed for each
+// repo with these fields:
+//
+//	.Org    the owner of the repo
+//	.Repo   the name of the repo
+//	.Base   the branch the changes were staged for
+//	.Branch the branch made for the changes (title and body only)
+//	.Files  the paths of the changed files in order
+type ProposeOptions struct {
+	// Branch is the template of the name of the branch made for the changes
+	// in each repo.  If the name is the same for changes staged for more than
+	// one branch of a repo, "-" and the name of the staged branch is added to
+	// keep them apart.
+	Branch string
+
+	// Message is the commit message.  The first line is the headline.
+	Message string
+
+	// Title is the template of the title of each pull request.
+	Title string
+
+	// Body is the template of the body of each pull request.
+	Body string
+
+	// Draft opens the pull requests as drafts.
+	Draft bool
+}
+
+// Proposal is the result of proposing the changes staged for one branch.
+type Proposal struct {
+	// Base is the path of the branch the changes were staged for, like
+	// org/repo/git/main.
+	Base string
+
+	// Number is the number of the pull request.
+	Number int
+
+	// Url is the url of the pull request.
+	Url string
+
+	// Err is why the changes couldn't be proposed, or nil.
+	Err error
+}
+
+// proposalData is the data the title and body templates are executed with.
+type proposalData struct {
+	Org    string
+	Repo   string
+	Base   string
+	Branch string
+	Files  []string
+}
+
+// createRefInput is the github CreateRefInput used by the createRef mutation.
+type createRefInput struct {
+	RepositoryId string `json:"repositoryId"`
+	Name         string `json:"name"`
+	Oid          string `json:"oid"`
+}
+
+// GetGraphQLType returns the name of the github type.
+func (createRefInput) GetGraphQLType() string {
+	return "CreateRefInput"
+}
+
+// deleteRefInput is the github DeleteRefInput used by the deleteRef mutation.
+type deleteRefInput struct {
+	RefId string `json:"refId"`
+}
+
+// GetGraphQLType returns the name of the github type.
+func (deleteRefInput) GetGraphQLType() string {
+	return "DeleteRefInput"
+}
+
+// pullRequestInput is the github CreatePullRequestInput used by the
+// createPullRequest mutation.
+type pullRequestInput struct {
+	RepositoryId string `json:"repositoryId"`
+	BaseRefName  string `json:"baseRefName"`
+	HeadRefName  string `json:"headRefName"`
+	Title        string `json:"title"`
+	Body         string `json:"body"`
+	Draft        bool   `json:"draft"`
+}
+
+// GetGraphQLType returns the name of the github type.
+func (pullRequestInput) GetGraphQLType() string {
+	return "CreatePullRequestInput"
+}
+
+// ProposeChanges opens a pull request for each branch with staged changes.
+// A new branch is made from the commit the branch is read at, the changes are
+// committed to it and a pull request is opened to merge it into the branch.
+// The changes that are proposed are no longer staged.
+//
+// A proposal is returned for each branch in order.  A failure for one branch
+// is reported in its proposal and doesn't stop the others.  The branch made
+// for the changes is deleted if they couldn't be proposed.
+func (gfs *FS) ProposeChanges(ctx context.Context, opts ProposeOptions) ([]Proposal, error) {
+	if len(opts.Branch) == 0 || len(strings.TrimSpace(opts.Message)) == 0 || len(opts.Title) == 0 {
+		return nil, fmt.Errorf("propose needs a branch, message and title %w", fs.ErrInvalid)
+	}
+
+	branch, err := template.New("branch").Parse(opts.Branch)
+	if err != nil {
+		return nil, fmt.Errorf("propose branch %w", err)
+	}
+	title, err := template.New("title").Parse(opts.Title)
+	if err != nil {
+		return nil, fmt.Errorf("propose title %w", err)
+	}
+	body, err := template.New("body").Parse(opts.Body)
+	if err != nil {
+		return nil, fmt.Errorf("propose body %w", err)
+	}
+
+	if err := gfs.connect(); err != nil {
+		return nil, fmt.Errorf("propose error connecting: %w", err)
+	}
+
+	keys := gfs.staged.branches()
+	names, err := gfs.proposalBranches(keys, branch)
+	if err != nil {
+		return nil, fmt.Errorf("propose branch %w", err)
+	}
+
+	var proposals []Proposal
+	for _, key := range keys {
+		p := Proposal{Base: key}
+		p.Number, p.Url, p.Err = gfs.propose(ctx, key, names[key], opts, title, body)
+		if p.Err == nil {
+			gfs.staged.drop(key)
+		}
+		proposals = append(proposals, p)
+	}
+
+	return proposals, nil
+}
+
+// proposalBranches names the branch made for the changes staged for each
+// branch.  Names used for more than one branch of a repo get the name of the
+// staged branch added so each is unique.
+func (gfs *FS) proposalBranches(keys []string, branch *template.Template) (map[string]string, error) {
+	names := make(map[string]string, len(keys))
+	uses := make(map[string]int, len(keys))
+	for _, key := range keys {
+		tree, _, _, _ := gfs.staged.get(key)
+
+		var b strings.Builder
+		err := branch.Execute(&b, proposalData{
+			Org:  tree.org,
+			Repo: tree.repo,
+			Base: tree.branch,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		names[key] = strings.TrimSpace(b.String())
+		uses[lockKey(tree.org, tree.repo, names[key])]++
+	}
+
+	for _, key := range keys {
+		tree, _, _, _ := gfs.staged.get(key)
+		if uses[lockKey(tree.org, tree.repo, names[key])] > 1 {
+			names[key] += "-" + tree.branch
+		}
+	}
+
+	return names, nil
+}
+
+// propose makes the branch, commit and pull request for the changes staged for
+// one branch and returns the number and url of the pull request.  The branch
+// is deleted if the changes can't be committed or the pull request opened.
+func (gfs *FS) propose(ctx context.Context, key, branch string, opts ProposeOptions, title, body *template.Template) (int, string, error) {
+	tree, additions, deletions, found := gfs.staged.get(key)
+	if !found {
+		return 0, "", fmt.Errorf("nothing is staged %w", fs.ErrInvalid)
+	}
+	if len(branch) == 0 {
+		return 0, "", fmt.Errorf("the branch name is empty %w", fs.ErrInvalid)
+	}
+
+	files := append(sortedKeys(additions), deletions...)
+	sort.Strings(files)
+
+	data := proposalData{
+		Org:    tree.org,
+		Repo:   tree.repo,
+		Base:   tree.branch,
+		Branch: branch,
+		Files:  files,
+	}
+
+	var t, b strings.Builder
+	if err := title.Execute(&t, data); err != nil {
+		return 0, "", err
+	}
+	if err := body.Execute(&b, data); err != nil {
+		return 0, "", err
+	}
+
+	head, err := treeHead(ctx, gfs, tree)
+	if err != nil {
+		return 0, "", err
+	}
+
+	id, err := repositoryId(ctx, gfs, tree.org, tree.repo)
+	if err != nil {
+		return 0, "", err
+	}
+
+	ref, err := createBranch(ctx, gfs, id, branch, head)
+	if err != nil {
+		return 0, "", err
+	}
+
+	_, _, err = commitOnBranch(ctx, gfs, tree.org, tree.repo, branch, head, opts.Message, additions, deletions)
+	if err != nil {
+		return 0, "", deleteBranch(ctx, gfs, ref, branch, err)
+	}
+
+	number, url, err := createPullRequest(ctx, gfs, pullRequestInput{
+		RepositoryId: id,
+		BaseRefName:  tree.branch,
+		HeadRefName:  branch,
+		Title:        strings.TrimSpace(t.String()),
+		Body:         b.String(),
+		Draft:        opts.Draft,
+	})
+	if err != nil {
+		return 0, "", deleteBranch(ctx, gfs, ref, branch, err)
+	}
+
+	return number, url, nil
+}
+
+// repositoryId fetches the node id of the repository used by mutations.
+func repositoryId(ctx context.Context, gfs *FS, org, repo string) (string, error) {
+	vars := map[string]any{
+		"owner": org,
+		"repo":  repo,
+	}
+
+	/*
+		query {
+		  repository(name: "repo", owner: "org") {
+		    id
+		  }
+		}
+	*/
+	var query struct {
+		Repository struct {
+			Id string
+		} `graphql:"repository(name: $repo, owner: $owner)"`
+	}
+
+	if err := gfs.gqlClient.Query(ctx, &query, vars); err != nil {
+		return "", err
+	}
+
+	return query.Repository.Id, nil
+}
+
+// createBranch makes a branch pointing at the commit and returns the node id
+// of the ref.
+func createBranch(ctx context.Context, gfs *FS, id, branch, oid string) (string, error) {
+	/*
+		mutation {
+		  createRef(input: {
+		    repositoryId: "R_kgDOH",
+		    name: "refs/heads/update-config",
+		    oid: "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+		  }) {
+		    ref {
+		      id
+		    }
+		  }
+		}
+	*/
+	var m struct {
+		CreateRef struct {
+			Ref struct {
+				Id string
+			}
+		} `graphql:"createRef(input: $input)"`
+	}
+
+	vars := map[string]any{
+		"input": createRefInput{
+			RepositoryId: id,
+			Name:         refPrefixBranches + branch,
+			Oid:          oid,
+		},
+	}
+
+	if err := gfs.gqlClient.Mutate(ctx, &m, vars); err != nil {
+		return "", err
+	}
+
+	return m.CreateRef.Ref.Id, nil
+}
+
+// deleteBranch deletes the branch made for changes that couldn't be proposed
+// and returns the reason they couldn't be proposed.
+func deleteBranch(ctx context.Context, gfs *FS, ref, branch string, cause error) error {
+	/*
+		mutation {
+		  deleteRef(input: {
+		    refId: "REF_kwDOH"
+		  }) {
+		    clientMutationId
+		  }
+		}
+	*/
+	var m struct {
+		DeleteRef struct {
+			ClientMutationId string
+		} `graphql:"deleteRef(input: $input)"`
+	}
+
+	vars := map[string]any{
+		"input": deleteRefInput{
+			RefId: ref,
+		},
+	}
+
+	if err := gfs.gqlClient.Mutate(ctx, &m, vars); err != nil {
+		return fmt.Errorf("%w, and the branch %s couldn't be deleted: %v", cause, branch, err)
+	}
+
+	return cause
+}
+
+// createPullRequest opens the pull request and returns its number and url.
+func createPullRequest(ctx context.Context, gfs *FS, input pullRequestInput) (int, string, error) {
+	/*
+		mutation {
+		  createPullRequest(input: {
+		    repositoryId: "R_kgDOH",
+		    baseRefName: "main",
+		    headRefName: "update-config",
+		    title: "Update the config",
+		    body: ""
+		  }) {
+		    pullRequest {
+		      number
+		      url
+		    }
+		  }
+		}
+	*/
+	var m struct {
+		CreatePullRequest struct {
+			PullRequest struct {
+				Number int
+				Url    string
+			}
+		} `graphql:"createPullRequest(input: $input)"`
+	}
+
+	vars := map[string]any{
+		"input": input,
+	}
+
+	if err := gfs.gqlClient.Mutate(ctx, &m, vars); err != nil {
+		return 0, "", err
+	}
+
+	return m.CreatePullRequest.PullRequest.Number, m.CreatePullRequest.PullRequest.Url, nil
+}
@@ -0,0 +1,280 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposeChanges(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, _, bodies := newTestFS(t, fsTest{
+		opts: []Option{WithOrg("org")},
+		payload: []string{
+			twoReposWithHeadsResponse,
+			repositoryIdResponse,
+			createRefResponse,
+			commitMutationResponse,
+			createPullRequestResponse,
+			invalidJsonResponse,
+		},
+	})
+
+	require.NoError(gfs.WriteFile("org/.github/git/main/a.yml", []byte("a: 1\n")))
+	require.NoError(gfs.WriteFile("org/.go-template/git/trunk/a.yml", []byte("a: 1\n")))
+
+	got, err := gfs.ProposeChanges(context.Background(), ProposeOptions{
+		Branch:  "update-config",
+		Message: "Update the config",
+		Title:   "Update the config of {{.Repo}}",
+		Body:    "Changes {{range .Files}}{{.}} {{end}}on {{.Base}}.",
+	})
+	require.NoError(err)
+	require.Len(got, 2)
+
+	assert.Equal(Proposal{
+		Base:   "org/.github/git/main",
+		Number: 7,
+		Url:    "https://github.com/org/.github/pull/7",
+	}, got[0])
+	assert.Equal("org/.go-template/git/trunk", got[1].Base)
+	assert.Error(got[1].Err)
+
+	assert.Contains((*bodies)[1], `"repo":".github"`)
+	assert.Contains((*bodies)[2], `"input":{"repositoryId":"R_kgDOH","name":"refs/heads/update-config","oid":"3333333333333333333333333333333333333333"}`)
+	assert.Contains((*bodies)[3], `"branch":{"repositoryNameWithOwner":"org/.github","branchName":"update-config"}`)
+	assert.Contains((*bodies)[3], `"expectedHeadOid":"3333333333333333333333333333333333333333"`)
+	assert.Contains((*bodies)[4], `"input":{"repositoryId":"R_kgDOH","baseRefName":"main","headRefName":"update-config",`+
+		`"title":"Update the config of .github","body":"Changes a.yml on main.","draft":false}`)
+
+	// Only the changes that failed are still staged.
+	assert.Equal([]string{"org/.go-template/git/trunk"}, gfs.staged.branches())
+}
+
+func TestProposeChangesDeletesBranch(t *testing.T) {
+	tests := []struct {
+		description string
+		payload     []string
+		deleteAt    int
+		errContains string
+	}{
+		{
+			description: "the commit fails",
+			payload: []string{
+				singleRepoWithHeadReponse,
+				repositoryIdResponse,
+				createRefResponse,
+				invalidJsonResponse,
+				deleteRefResponse,
+			},
+			deleteAt: 4,
+		}, {
+			description: "the pull request fails",
+			payload: []string{
+				singleRepoWithHeadReponse,
+				repositoryIdResponse,
+				createRefResponse,
+				commitMutationResponse,
+				invalidJsonResponse,
+				deleteRefResponse,
+			},
+			deleteAt: 5,
+		}, {
+			description: "the branch can't be deleted either",
+			payload: []string{
+				singleRepoWithHeadReponse,
+				repositoryIdResponse,
+				createRefResponse,
+				invalidJsonResponse,
+				invalidJsonResponse,
+			},
+			deleteAt:    4,
+			errContains: "the branch update-config couldn't be deleted",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			gfs, _, bodies := newTestFS(t, fsTest{
+				opts:    []Option{WithRepo("org", "repo")},
+				payload: tc.payload,
+			})
+
+			require.NoError(gfs.WriteFile("org/repo/git/main/a.yml", []byte("a: 1\n")))
+
+			got, err := gfs.ProposeChanges(context.Background(), ProposeOptions{
+				Branch:  "update-config",
+				Message: "Update the config",
+				Title:   "Update the config",
+			})
+			require.NoError(err)
+			require.Len(got, 1)
+			assert.Error(got[0].Err)
+			if len(tc.errContains) > 0 {
+				assert.ErrorContains(got[0].Err, tc.errContains)
+			}
+
+			require.Len(*bodies, tc.deleteAt+1)
+			assert.Contains((*bodies)[tc.deleteAt], "deleteRef(input: $input)")
+			assert.Contains((*bodies)[tc.deleteAt], `"input":{"refId":"REF_kwDOH"}`)
+
+			// The changes are still staged.
+			assert.Equal([]string{"org/repo/git/main"}, gfs.staged.branches())
+		})
+	}
+}
+
+func TestProposeChangesBranchNames(t *testing.T) {
+	tests := []struct {
+		description string
+		branch      string
+		expect      []string
+	}{
+		{
+			description: "the same name for two branches of a repo",
+			branch:      "update-config",
+			expect:      []string{"update-config-dev", "update-config-main"},
+		}, {
+			description: "a name for each branch from the template",
+			branch:      "update-config/{{.Base}}",
+			expect:      []string{"update-config/dev", "update-config/main"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			gfs, _, bodies := newTestFS(t, fsTest{
+				opts: []Option{WithRepo("org", "repo", "main", "dev")},
+				payload: []string{
+					singleRepoWithBranchHeadReponse,
+					singleRepoWithBranchHeadReponse,
+					repositoryIdResponse,
+					createRefResponse,
+					commitMutationResponse,
+					createPullRequestResponse,
+					repositoryIdResponse,
+					createRefResponse,
+					commitMutationResponse,
+					createPullRequestResponse,
+				},
+			})
+
+			require.NoError(gfs.WriteFile("org/repo/git/main/a.yml", []byte("a: 1\n")))
+			require.NoError(gfs.WriteFile("org/repo/git/dev/a.yml", []byte("a: 1\n")))
+
+			got, err := gfs.ProposeChanges(context.Background(), ProposeOptions{
+				Branch:  tc.branch,
+				Message: "Update the config",
+				Title:   "Update {{.Base}} with {{.Branch}}",
+			})
+			require.NoError(err)
+			require.Len(got, 2)
+			assert.NoError(got[0].Err)
+			assert.NoError(got[1].Err)
+
+			require.Len(*bodies, 10)
+			assert.Contains((*bodies)[3], `"name":"refs/heads/`+tc.expect[0]+`"`)
+			assert.Contains((*bodies)[5], `"title":"Update dev with `+tc.expect[0]+`"`)
+			assert.Contains((*bodies)[7], `"name":"refs/heads/`+tc.expect[1]+`"`)
+			assert.Contains((*bodies)[9], `"title":"Update main with `+tc.expect[1]+`"`)
+		})
+	}
+}
+
+func TestProposeChangesErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		opts        ProposeOptions
+		errIs       error
+	}{
+		{
+			description: "no branch",
+			opts:        ProposeOptions{Message: "m", Title: "t"},
+			errIs:       fs.ErrInvalid,
+		}, {
+			description: "no message",
+			opts:        ProposeOptions{Branch: "b", Message: " ", Title: "t"},
+			errIs:       fs.ErrInvalid,
+		}, {
+			description: "no title",
+			opts:        ProposeOptions{Branch: "b", Message: "m"},
+			errIs:       fs.ErrInvalid,
+		}, {
+			description: "an invalid title",
+			opts:        ProposeOptions{Branch: "b", Message: "m", Title: "{{.Repo"},
+		}, {
+			description: "an invalid branch",
+			opts:        ProposeOptions{Branch: "{{.Base", Message: "m", Title: "t"},
+		}, {
+			description: "an invalid body",
+			opts:        ProposeOptions{Branch: "b", Message: "m", Title: "t", Body: "{{end}}"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gfs, requests, _ := newTestFS(t, fsTest{})
+
+			got, err := gfs.ProposeChanges(context.Background(), tc.opts)
+			assert.Error(err)
+			assert.Nil(got)
+			if tc.errIs != nil {
+				assert.ErrorIs(err, tc.errIs)
+			}
+			assert.Empty(*requests)
+		})
+	}
+}
+
+var repositoryIdResponse = `{
+  "data": {
+    "repository": {
+      "id": "R_kgDOH"
+    }
+  }
+}`
+
+var createRefResponse = `{
+  "data": {
+    "createRef": {
+      "ref": {
+        "id": "REF_kwDOH"
+      }
+    }
+  }
+}`
+
+var deleteRefResponse = `{
+  "data": {
+    "deleteRef": {
+      "clientMutationId": ""
+    }
+  }
+}`
+
+var createPullRequestResponse = `{
+  "data": {
+    "createPullRequest": {
+      "pullRequest": {
+        "number": 7,
+        "url": "https://github.com/org/.github/pull/7"
+      }
+    }
+  }
+}`
@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dirNamePullHead  = "head"
+	dirNamePullBase  = "base"
+	fileNamePullDiff = "diff.patch"
+	fileNamePullInfo = "pull.json"
+	fileNamePullList = "files.txt"
+)
+
+// pullInfo is the metadata about a pull request written to pull.json.
+type pullInfo struct {
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	Author     string    `json:"author"`
+	State      string    `json:"state"`
+	Labels     []string  `json:"labels"`
+	Url        string    `json:"url"`
+	HeadRef    string    `json:"headRef"`
+	HeadCommit string    `json:"headCommit"`
+	BaseRef    string    `json:"baseRef"`
+	BaseCommit string    `json:"baseCommit"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// getPullsDir fetches the pull requests and makes each into a directory
+// structure that is linked to the filesystem.  The list of changed files for
+// each pull request is fetched when files.txt is opened.
+func getPullsDir(gfs *FS, d *dir) error {
+	vars := map[string]any{
+		"owner": d.org,
+		"repo":  d.repo,
+		"count": 100,
+		"after": (*string)(nil),
+	}
+
+	/*
+		query {
+		  repository(name: "repo", owner: "org") {
+		    pullRequests(first: 100, orderBy: {field: CREATED_AT, direction: DESC}) {
+		      edges {
+		        node {
+		          number
+		          title
+		          state
+		          url
+		          createdAt
+		          updatedAt
+		          author {
+		            login
+		          }
+		          labels(first: 100) {
+		            edges {
+		              node {
+		                name
+		              }
+		            }
+		          }
+		          headRefName
+		          headRefOid
+		          baseRefName
+		          baseRefOid
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	more := true
+	for more {
+		var query struct {
+			Repository struct {
+				PullRequests struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+					Edges []struct {
+						Node struct {
+							Number    int
+							Title     string
+							State     string
+							Url       string
+							CreatedAt time.Time
+							UpdatedAt time.Time
+							Author    struct {
+								Login string
+							}
+							Labels struct {
+								Edges []struct {
+									Node struct {
+										Name string
+									}
+								}
+							} `graphql:"labels(first: 100)"`
+							HeadRefName string
+							HeadRefOid  string
+							BaseRefName string
+							BaseRefOid  string
+						}
+					}
+				} `graphql:"pullRequests(first: $count, after: $after, orderBy: {field: CREATED_AT, direction: DESC})"`
+			} `graphql:"repository(name: $repo, owner: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return err
+		}
+
+		for _, edge := range query.Repository.PullRequests.Edges {
+			pr := edge.Node
+			info := pullInfo{
+				Number:     pr.Number,
+				Title:      pr.Title,
+				Author:     pr.Author.Login,
+				State:      pr.State,
+				Labels:     []string{},
+				Url:        pr.Url,
+				HeadRef:    pr.HeadRefName,
+				HeadCommit: pr.HeadRefOid,
+				BaseRef:    pr.BaseRefName,
+				BaseCommit: pr.BaseRefOid,
+				CreatedAt:  pr.CreatedAt,
+				UpdatedAt:  pr.UpdatedAt,
+			}
+			for _, label := range pr.Labels.Edges {
+				info.Labels = append(info.Labels, label.Node.Name)
+			}
+
+			buf, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			number := strconv.Itoa(pr.Number)
+			prDir := d.newDir(number, withDirModTime(pr.UpdatedAt))
+
+			// The head commit of a pull request from a fork is also available
+			// in the base repository, so both trees are read from there.
+			prDir.newDir(dirNamePullHead, withCommit(pr.HeadRefOid), notInPath(), gfs.treeFetcher(d.size))
+			prDir.newDir(dirNamePullBase, withCommit(pr.BaseRefOid), notInPath(), gfs.treeFetcher(d.size))
+
+			prDir.addFile(fileNamePullInfo, withContent(buf), withModTime(pr.UpdatedAt))
+			prDir.addFile(fileNamePullDiff,
+				withUrl(strings.Join([]string{gfs.restUrl, "repos", d.org, d.repo, "pulls", number}, "/")),
+				withAccept("application/vnd.github.diff"),
+				withUnknownSize(),
+				withModTime(pr.UpdatedAt))
+			prDir.addFile(fileNamePullList,
+				withFileFetcher(pullFilesFetcher(pr.Number)),
+				withModTime(pr.UpdatedAt))
+		}
+
+		more = query.Repository.PullRequests.PageInfo.HasNextPage
+		vars["after"] = query.Repository.PullRequests.PageInfo.EndCursor
+	}
+
+	return nil
+}
+
+// pullFilesFetcher returns the fetcher for files.txt that lists the files
+// changed by a pull request, one path per line.
+func pullFilesFetcher(number int) func(*FS, *file) ([]byte, error) {
+	return func(gfs *FS, f *file) ([]byte, error) {
+		return getPullFiles(gfs, f.owner, f.repo, number)
+	}
+}
+
+// getPullFiles fetches the list of files changed by a pull request.
+func getPullFiles(gfs *FS, org, repo string, number int) ([]byte, error) {
+	vars := map[string]any{
+		"owner":  org,
+		"repo":   repo,
+		"number": number,
+		"count":  100,
+		"after":  (*string)(nil),
+	}
+
+	/*
+		query {
+		  repository(name: "repo", owner: "org") {
+		    pullRequest(number: 1) {
+		      files(first: 100) {
+		        edges {
+		          node {
+		            path
+		          }
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	var list strings.Builder
+	more := true
+	for more {
+		var query struct {
+			Repository struct {
+				PullRequest struct {
+					Files struct {
+						PageInfo struct {
+							HasNextPage bool
+							EndCursor   string
+						}
+						Edges []struct {
+							Node struct {
+								Path string
+							}
+						}
+					} `graphql:"files(first: $count, after: $after)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(name: $repo, owner: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return nil, err
+		}
+
+		for _, edge := range query.Repository.PullRequest.Files.Edges {
+			list.WriteString(edge.Node.Path)
+			list.WriteString("\n")
+		}
+
+		more = query.Repository.PullRequest.Files.PageInfo.HasNextPage
+		vars["after"] = query.Repository.PullRequest.Files.PageInfo.EndCursor
+	}
+
+	return []byte(list.String()), nil
+}
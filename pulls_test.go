@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"testing"
+)
+
+func TestPulls(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "fetch the pull request metadata and files",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithPullsReponse, pullsResponse001, pullsResponse002, pullFilesResponse001, pullFilesResponse002},
+			expect:      []string{"org/repo/pulls"},
+			unexpected:  []string{"org/repo/pulls/3"},
+			contents: map[string]string{
+				"org/repo/pulls/2/files.txt": ".github/workflows/ci.yml\nREADME.md\nconfig.yml\n",
+				"org/repo/pulls/2/pull.json": pull2Json,
+			},
+		}, {
+			description: "fetch the pull request diff",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithPullsReponse, pullsResponse002, pullDiffResponse},
+			contents: map[string]string{
+				"org/repo/pulls/1/diff.patch": pullDiffResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"GET /repos/org/repo/pulls/1",
+			},
+		}, {
+			description: "fetch the pull request head and base trees a file at a time",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload: []string{singleRepoWithPullsReponse, pullsResponse002,
+				baseDirectoryResponse, readmeResponse,
+				baseDirectoryResponse, readmeResponse},
+			contents: map[string]string{
+				"org/repo/pulls/1/base/README.md": readmeResponse,
+				"org/repo/pulls/1/head/README.md": readmeResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"POST /",
+				"GET /org/repo/bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb/README.md",
+				"POST /",
+				"GET /org/repo/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa/README.md",
+			},
+		}, {
+			description: "the files are only listed when files.txt is read",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithPullsReponse, pullsResponse002},
+			expect:      []string{"org/repo/pulls/1", "org/repo/pulls/1/pull.json"},
+			requests: []string{
+				"POST /",
+				"POST /",
+			},
+		}, {
+			description: "a repo without pull requests has no pulls directory",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse},
+			unexpected:  []string{"org/repo/pulls"},
+		}, {
+			description: "fetch the pull requests, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithPullsReponse, invalidJsonResponse},
+			expect:      []string{"org/repo/pulls/1"},
+			expectErr:   true,
+		}, {
+			description: "fetch the pull request files, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithPullsReponse, pullsResponse002, invalidJsonResponse},
+			expect:      []string{"org/repo/pulls/1/files.txt"},
+			expectErr:   true,
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+var singleRepoWithPullsReponse = `{
+  "data": {
+    "repository": {
+      "diskUsage": 18,
+      "isArchived": false,
+      "isDisabled": false,
+      "nameWithOwner": "org/repo",
+      "defaultBranchRef": {
+        "name": "main"
+      },
+      "pullRequests": {
+        "totalCount": 2
+      },
+      "releases": {
+        "totalCount": 0
+      }
+    }
+  }
+}`
+
+var pullsResponse001 = `{
+  "data": {
+    "repository": {
+      "pullRequests": {
+        "edges": [
+          {
+            "node": {
+              "number": 2,
+              "title": "Update the config",
+              "state": "OPEN",
+              "url": "https://github.com/org/repo/pull/2",
+              "createdAt": "2023-05-01T10:00:00Z",
+              "updatedAt": "2023-05-02T11:00:00Z",
+              "author": {
+                "login": "octocat"
+              },
+              "labels": {
+                "edges": [
+                  { "node": { "name": "config" } },
+                  { "node": { "name": "needs review" } }
+                ]
+              },
+              "headRefName": "feature/config",
+              "headRefOid": "cccccccccccccccccccccccccccccccccccccccc",
+              "baseRefName": "main",
+              "baseRefOid": "dddddddddddddddddddddddddddddddddddddddd"
+            }
+          }
+        ],
+        "pageInfo": {
+          "endCursor": "MQ",
+          "hasNextPage": true
+        }
+      }
+    }
+  }
+}`
+
+var pullsResponse002 = `{
+  "data": {
+    "repository": {
+      "pullRequests": {
+        "edges": [
+          {
+            "node": {
+              "number": 1,
+              "title": "Initial commit",
+              "state": "MERGED",
+              "url": "https://github.com/org/repo/pull/1",
+              "createdAt": "2023-04-01T10:00:00Z",
+              "updatedAt": "2023-04-02T11:00:00Z",
+              "author": {
+                "login": "hubot"
+              },
+              "labels": {
+                "edges": []
+              },
+              "headRefName": "initial",
+              "headRefOid": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+              "baseRefName": "main",
+              "baseRefOid": "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+            }
+          }
+        ],
+        "pageInfo": {
+          "endCursor": "Mg",
+          "hasNextPage": false
+        }
+      }
+    }
+  }
+}`
+
+var pullFilesResponse001 = `{
+  "data": {
+    "repository": {
+      "pullRequest": {
+        "files": {
+          "edges": [
+            { "node": { "path": ".github/workflows/ci.yml" } },
+            { "node": { "path": "README.md" } }
+          ],
+          "pageInfo": {
+            "endCursor": "Mg",
+            "hasNextPage": true
+          }
+        }
+      }
+    }
+  }
+}`
+
+var pullFilesResponse002 = `{
+  "data": {
+    "repository": {
+      "pullRequest": {
+        "files": {
+          "edges": [
+            { "node": { "path": "config.yml" } }
+          ],
+          "pageInfo": {
+            "endCursor": "Mw",
+            "hasNextPage": false
+          }
+        }
+      }
+    }
+  }
+}`
+
+var pullDiffResponse = `diff --git a/config.yml b/config.yml
+index e69de29..d95f3ad 100644
+--- a/config.yml
++++ b/config.yml
+@@ -0,0 +1 @@
++enabled: true
+`
+
+var pull2Json = `{
+  "number": 2,
+  "title": "Update the config",
+  "author": "octocat",
+  "state": "OPEN",
+  "labels": [
+    "config",
+    "needs review"
+  ],
+  "url": "https://github.com/org/repo/pull/2",
+  "headRef": "feature/config",
+  "headCommit": "cccccccccccccccccccccccccccccccccccccccc",
+  "baseRef": "main",
+  "baseCommit": "dddddddddddddddddddddddddddddddddddddddd",
+  "createdAt": "2023-05-01T10:00:00Z",
+  "updatedAt": "2023-05-02T11:00:00Z"
+}`
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+)
+
+const (
+	refPrefixBranches = "refs/heads/"
+	refPrefixTags     = "refs/tags/"
+)
+
+// addBranchGlobs configures the git directory to lazily discover the branches
+// that match the globs, or all of the branches.
+func (d *dir) addBranchGlobs(all bool, globs []string) {
+	d.allBranches = d.allBranches || all
+	d.globs = append(d.globs, globs...)
+	d.fetchFn = getBranchesDir
+}
+
+// matchesBranch determines if the branch name matches the configured globs.
+func (d *dir) matchesBranch(name string) bool {
+	if d.allBranches {
+		return true
+	}
+	for _, glob := range d.globs {
+		if ok, _ := path.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// getBranchesDir lists the branches of the repository and adds the ones that
+// match the configured globs to the git directory.
+func getBranchesDir(gfs *FS, d *dir) error {
+	return gfs.forEachRef(d, refPrefixBranches, func(name, oid string) error {
+		if !d.matchesBranch(name) {
+			return nil
+		}
+
+		oid, err := gfs.pin(d.org, d.repo, name, oid)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		d.mkref(name, withBranch(name), withCommit(oid), notInPath(), gfs.treeFetcher(d.size))
+		return nil
+	})
+}
+
+// getTagsDir lists the tags of the repository and adds each of them to the
+// tags directory.  The tags are pinned to the commit they point to, so the
+// trees are fetched the same way as the branches.
+func getTagsDir(gfs *FS, d *dir) error {
+	return gfs.forEachRef(d, refPrefixTags, func(name, oid string) error {
+		d.mkref(name, withCommit(oid), notInPath(), gfs.treeFetcher(d.size))
+		return nil
+	})
+}
+
+// forEachRef calls github and lists all the refs with the specified prefix,
+// calling fn with the name of each ref with the prefix removed and the commit
+// the ref points to.  Annotated tags are peeled to the commit they tag.  Any
+// error returned by fn stops the listing.
+func (gfs *FS) forEachRef(d *dir, prefix string, fn func(name, oid string) error) error {
+	vars := map[string]any{
+		"owner":  d.org,
+		"repo":   d.repo,
+		"prefix": prefix,
+		"count":  100,
+		"after":  (*string)(nil),
+	}
+
+	/*
+		query {
+		  repository(name: "repo", owner: "org") {
+		    refs(refPrefix: "refs/tags/", first: 100) {
+		      edges {
+		        node {
+		          name
+		          target {
+		            oid
+		            ... on Tag {
+		              target {
+		                oid
+		              }
+		            }
+		          }
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	more := true
+	for more {
+		var query struct {
+			Repository struct {
+				Refs struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+					Edges []struct {
+						Node struct {
+							Name   string
+							Target struct {
+								Oid string
+								Tag struct {
+									Target struct {
+										Oid string
+									}
+								} `graphql:"... on Tag"`
+							}
+						}
+					}
+				} `graphql:"refs(refPrefix: $prefix, first: $count, after: $after, orderBy: {field: ALPHABETICAL, direction: ASC})"`
+			} `graphql:"repository(name: $repo, owner: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return err
+		}
+
+		for _, edge := range query.Repository.Refs.Edges {
+			oid := edge.Node.Target.Oid
+			if peeled := edge.Node.Target.Tag.Target.Oid; len(peeled) > 0 {
+				oid = peeled
+			}
+			if err := fn(edge.Node.Name, oid); err != nil {
+				return err
+			}
+		}
+
+		more = query.Repository.Refs.PageInfo.HasNextPage
+		vars["after"] = query.Repository.Refs.PageInfo.EndCursor
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"testing"
+)
+
+func TestBranches(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "fetch the branches matching the globs",
+			opts:        []Option{WithBranches("org", "repo", "release/*", "main"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoReponse, branchesResponse001, branchesResponse002, baseDirectoryResponse, baseDirectoryResponse, readmeResponse},
+			expect:      []string{"org/repo/git/release", "org/repo/git/main", "org/repo/git/release/2.0/README.md"},
+			unexpected:  []string{"org/repo/git/feature/x", "org/repo/git/release/old/1.0"},
+		}, {
+			description: "fetch all the branches",
+			opts:        []Option{WithBranches("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoReponse, branchesResponse001, branchesResponse002, baseDirectoryResponse},
+			expect:      []string{"org/repo/git/feature", "org/repo/git/release/old/1.0"},
+		}, {
+			description: "fetch a named branch and the branches matching the globs",
+			opts:        []Option{WithRepo("org", "repo", "feature/x"), WithBranches("org", "repo", "main"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoReponse, singleRepoReponse, branchesResponse001, branchesResponse002, baseDirectoryResponse, baseDirectoryResponse},
+			expect:      []string{"org/repo/git/main", "org/repo/git/feature/x"},
+			unexpected:  []string{"org/repo/git/release/2.0"},
+		}, {
+			description: "fetch the branches, but there was a json error",
+			opts:        []Option{WithBranches("org", "repo")},
+			payload:     []string{singleRepoReponse, invalidJsonResponse},
+			expect:      []string{"org/repo/git/main"},
+			expectErr:   true,
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+func TestTags(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "fetch a tag all at once",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithTagsReponse, tagsResponse, commitTarballResponse, fullRepoTarball},
+			expect:      []string{"org/repo/tags/release/v2.0.0", "org/repo/tags/release/v2.0.0/a"},
+			unexpected:  []string{"org/repo/tags/v2.0.0"},
+		}, {
+			description: "fetch an annotated tag a file at a time",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoWithTagsReponse, tagsResponse, baseDirectoryResponse, readmeResponse},
+			contents: map[string]string{
+				"org/repo/tags/release/v2.0.0/README.md": readmeResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"POST /",
+				"GET /org/repo/2222222222222222222222222222222222222222/README.md",
+			},
+		}, {
+			description: "fetch a lightweight tag a file at a time",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoWithTagsReponse, tagsResponse, baseDirectoryResponse, readmeResponse},
+			contents: map[string]string{
+				"org/repo/tags/v1.0.0/README.md": readmeResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"POST /",
+				"GET /org/repo/3333333333333333333333333333333333333333/README.md",
+			},
+		}, {
+			description: "a repo without tags has no tags directory",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse},
+			unexpected:  []string{"org/repo/tags"},
+		}, {
+			description: "fetch the tags, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithTagsReponse, invalidJsonResponse},
+			expect:      []string{"org/repo/tags/v1.0.0"},
+			expectErr:   true,
+		}, {
+			description: "fetch a tag all at once, but there was a json error",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithTagsReponse, tagsResponse, invalidJsonResponse},
+			expect:      []string{"org/repo/tags/v1.0.0"},
+			expectErr:   true,
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+var branchesResponse001 = `{
+  "data": {
+    "repository": {
+      "refs": {
+        "edges": [
+          { "node": { "name": "feature/x" } },
+          { "node": { "name": "main" } },
+          { "node": { "name": "release/1.0" } }
+        ],
+        "pageInfo": {
+          "endCursor": "Mw",
+          "hasNextPage": true
+        }
+      }
+    }
+  }
+}`
+
+var branchesResponse002 = `{
+  "data": {
+    "repository": {
+      "refs": {
+        "edges": [
+          { "node": { "name": "release/old/1.0" } },
+          { "node": { "name": "release/2.0" } }
+        ],
+        "pageInfo": {
+          "endCursor": "NQ",
+          "hasNextPage": false
+        }
+      }
+    }
+  }
+}`
+
+var singleRepoWithTagsReponse = `{
+  "data": {
+    "repository": {
+      "diskUsage": 18,
+      "isArchived": false,
+      "isDisabled": false,
+      "nameWithOwner": "org/repo",
+      "defaultBranchRef": {
+        "name": "main"
+      },
+      "releases": {
+        "totalCount": 0
+      },
+      "tags": {
+        "totalCount": 2
+      }
+    }
+  }
+}`
+
+var tagsResponse = `{
+  "data": {
+    "repository": {
+      "refs": {
+        "edges": [
+          {
+            "node": {
+              "name": "release/v2.0.0",
+              "target": {
+                "oid": "1111111111111111111111111111111111111111",
+                "target": {
+                  "oid": "2222222222222222222222222222222222222222"
+                }
+              }
+            }
+          },
+          {
+            "node": {
+              "name": "v1.0.0",
+              "target": {
+                "oid": "3333333333333333333333333333333333333333"
+              }
+            }
+          }
+        ],
+        "pageInfo": {
+          "endCursor": "Mg",
+          "hasNextPage": false
+        }
+      }
+    }
+  }
+}`
+
+var commitTarballResponse = `{
+  "data": {
+    "repository": {
+      "object": {
+        "tarballUrl": "OVERWRITEURL"
+      }
+    }
+  }
+}`
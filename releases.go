@@ -0,0 +1,776 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	releaseDescription   = "description.md"
+	releaseMetadataFile  = "release.json"
+	releaseSourceTarball = "source.tar.gz"
+	releaseSourceZip     = "source.zip"
+	dirNameDraft         = "draft"
+	dirNamePrerelease    = "prerelease"
+	dirNameLatest        = "latest"
+)
+
+// ReleaseFilter selects which releases are included in the releases
+// directories.  Only the published, stable releases are included by default.
+type ReleaseFilter int
+
+const (
+	// ReleasePrereleases includes the releases marked as prereleases.
+	ReleasePrereleases ReleaseFilter = 1 << iota
+
+	// ReleaseDrafts includes the draft releases.  Drafts are only visible to
+	// tokens that can push to the repo.
+	ReleaseDrafts
+
+	// ReleasesSeparated puts the prereleases and drafts in the
+	// releases/prerelease and releases/draft directories instead of mixing
+	// them in with the other releases.
+	ReleasesSeparated
+)
+
+// WithReleaseFilter sets which releases are included in the releases
+// directories.  The filters are combined, so
+//
+//	WithReleaseFilter(ReleasePrereleases, ReleasesSeparated)
+//
+// includes the prereleases in the releases/prerelease directory.
+func WithReleaseFilter(filters ...ReleaseFilter) Option {
+	return func(gfs *FS) {
+		gfs.releases = 0
+		for _, filter := range filters {
+			gfs.releases |= filter
+		}
+	}
+}
+
+// WithReleaseConstraint limits the releases included in the releases
+// directories to the ones with a semantic version tag, like v1.2.3, that
+// matches the constraint.  The constraint is a comma separated list of
+// comparisons that must all match, like ">=1.2, <2".  The operators are =,
+// !=, >, >=, < and <=, and a version without an operator must match exactly.
+// A prerelease only matches if a comparison has a prerelease of the same
+// version, like ">=2.0.0-rc.1".  An empty constraint matches every release.
+// An invalid constraint is reported when the filesystem connects to github.
+func WithReleaseConstraint(constraint string) Option {
+	return func(gfs *FS) {
+		c, err := parseConstraint(constraint)
+		if err != nil {
+			gfs.optionErr = err
+			return
+		}
+		gfs.constraint = c
+	}
+}
+
+// WithMaxReleases limits the releases read for each repo to the n most
+// recently created ones.  The filters and constraint apply to the releases
+// read, so fewer than n may be included.  The default of 0 reads every
+// release.
+func WithMaxReleases(n int) Option {
+	return func(gfs *FS) {
+		gfs.maxReleases = n
+	}
+}
+
+// ReleaseInfo is returned by Sys() for the directory of a release and the
+// files in it.
+type ReleaseInfo struct {
+	// Tag is the name of the tag of the release.
+	Tag string
+
+	// Draft is true if the release is a draft.
+	Draft bool
+
+	// Prerelease is true if the release is marked as a prerelease.
+	Prerelease bool
+}
+
+// ReleaseOptions describes the release made by CreateRelease().
+type ReleaseOptions struct {
+	// Name is the title of the release.  The tag is used if it is empty.
+	Name string
+
+	// Body is the description of the release in markdown.
+	Body string
+
+	// Target is the branch or commit the tag is made from if the tag doesn't
+	// exist yet.  The default branch is used if it is empty.
+	Target string
+
+	// Draft makes the release a draft that isn't published.
+	Draft bool
+
+	// Prerelease marks the release as a prerelease.
+	Prerelease bool
+}
+
+// releaseInput is the body of the REST request that creates a release.
+type releaseInput struct {
+	TagName         string `json:"tag_name"`
+	TargetCommitish string `json:"target_commitish,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Body            string `json:"body"`
+	Draft           bool   `json:"draft"`
+	Prerelease      bool   `json:"prerelease"`
+}
+
+// restRelease is a release as returned by the REST API.
+type restRelease struct {
+	Id         int64       `json:"id"`
+	TagName    string      `json:"tag_name"`
+	Body       string      `json:"body"`
+	Draft      bool        `json:"draft"`
+	Prerelease bool        `json:"prerelease"`
+	UploadUrl  string      `json:"upload_url"`
+	Assets     []restAsset `json:"assets"`
+}
+
+// releaseMetadata is the metadata about a release written to release.json.
+type releaseMetadata struct {
+	Tag         string     `json:"tag"`
+	Name        string     `json:"name"`
+	Author      string     `json:"author"`
+	Commit      string     `json:"commit,omitempty"`
+	Draft       bool       `json:"draft"`
+	Prerelease  bool       `json:"prerelease"`
+	Latest      bool       `json:"latest"`
+	Url         string     `json:"url"`
+	TarballUrl  string     `json:"tarballUrl"`
+	ZipballUrl  string     `json:"zipballUrl"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+}
+
+// modTime returns when the release was published, or when it was created if
+// it is a draft.
+func (m releaseMetadata) modTime() time.Time {
+	if m.PublishedAt != nil {
+		return *m.PublishedAt
+	}
+	return m.CreatedAt
+}
+
+// restAsset is an asset of a release as returned by the REST API.
+type restAsset struct {
+	Id                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int    `json:"size"`
+	BrowserDownloadUrl string `json:"browser_download_url"`
+}
+
+// CreateRelease creates a release of the repo for the tag.  The tag is made
+// from the target if it doesn't exist yet.  The releases of the repo are read
+// again the next time they are used.
+func (gfs *FS) CreateRelease(ctx context.Context, org, repo, tag string, opts ReleaseOptions) error {
+	if len(org) == 0 || len(repo) == 0 || len(tag) == 0 {
+		return fmt.Errorf("create release needs an org, repo and tag %w", fs.ErrInvalid)
+	}
+
+	if err := gfs.connect(); err != nil {
+		return fmt.Errorf("create release error connecting: %w", err)
+	}
+
+	input := releaseInput{
+		TagName:         tag,
+		TargetCommitish: opts.Target,
+		Name:            opts.Name,
+		Body:            opts.Body,
+		Draft:           opts.Draft,
+		Prerelease:      opts.Prerelease,
+	}
+
+	if err := restJSON(ctx, gfs, http.MethodPost, releasesUrl(gfs, org, repo), input, nil); err != nil {
+		return fmt.Errorf("create release %s %w", tag, err)
+	}
+
+	gfs.refetchReleases(org, repo)
+
+	return nil
+}
+
+// UploadAsset uploads the size bytes read from r as the named asset of a
+// release, like org/repo/releases/v1.0.0/app.tar.gz or
+// org/repo/releases/draft/v1.0.0/app.tar.gz.  An asset with the same
+// name is replaced, and is kept if the new asset can't be uploaded.  Uploading
+// description.md replaces the description of the release instead.
+func (gfs *FS) UploadAsset(ctx context.Context, name string, r io.Reader, size int64) error {
+	if err := gfs.writeRelease(ctx, name, r, size); err != nil {
+		return fmt.Errorf("upload %s %w", name, err)
+	}
+	return nil
+}
+
+// releasePath splits the path of a file in a release into the org, repo, tag
+// and file name.  The tag may be in the draft or prerelease directory.
+func releasePath(name string) (org, repo, tag, file string, ok bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) < 5 || parts[2] != dirNameReleases {
+		return "", "", "", "", false
+	}
+	if len(parts) == 6 && (parts[3] == dirNameDraft || parts[3] == dirNamePrerelease) {
+		parts = append(parts[:3], parts[4:]...)
+	}
+	if len(parts) != 5 {
+		return "", "", "", "", false
+	}
+	return parts[0], parts[1], parts[3], parts[4], true
+}
+
+// writeRelease uploads the asset or replaces the description of the release
+// and updates the releases directory to match.
+func (gfs *FS) writeRelease(ctx context.Context, name string, r io.Reader, size int64) error {
+	if !fs.ValidPath(name) || size < 0 {
+		return fs.ErrInvalid
+	}
+
+	org, repo, tag, file, ok := releasePath(name)
+	if !ok {
+		return fmt.Errorf("not a file in a release %w", fs.ErrPermission)
+	}
+
+	if err := gfs.connect(); err != nil {
+		return err
+	}
+
+	rel, err := getRelease(ctx, gfs, org, repo, tag)
+	if err != nil {
+		return err
+	}
+
+	if file == releaseDescription {
+		body, err := io.ReadAll(io.LimitReader(r, size))
+		if err != nil {
+			return err
+		}
+
+		u := releasesUrl(gfs, org, repo, strconv.FormatInt(rel.Id, 10))
+		input := map[string]string{"body": string(body)}
+		if err := restJSON(ctx, gfs, http.MethodPatch, u, input, &rel); err != nil {
+			return err
+		}
+
+		if d := gfs.knownRelease(org, repo, rel); d != nil {
+			d.addFile(releaseDescription,
+				withContent([]byte(rel.Body)),
+				withModTime(d.modTime),
+				withSys(d.sys))
+		}
+		return nil
+	}
+
+	var old *restAsset
+	for i := range rel.Assets {
+		if rel.Assets[i].Name == file {
+			old = &rel.Assets[i]
+		}
+	}
+
+	// Assets can't be overwritten, so a replacement is uploaded under a
+	// temporary name and renamed once the old asset is deleted.  The old
+	// asset is kept if the upload fails.
+	upload := file
+	if old != nil {
+		upload = file + "." + strconv.FormatInt(old.Id, 10) + ".tmp"
+	}
+
+	br := bufio.NewReader(r)
+	var asset restAsset
+	err = restSend(ctx, gfs, http.MethodPost, assetUploadUrl(rel.UploadUrl, upload),
+		assetContentType(file, br), br, size, &asset)
+	if err != nil {
+		return err
+	}
+
+	if old != nil {
+		if asset, err = replaceAsset(ctx, gfs, org, repo, *old, asset); err != nil {
+			return err
+		}
+	}
+
+	if d := gfs.knownRelease(org, repo, rel); d != nil {
+		d.addFile(asset.Name,
+			withSize(asset.Size),
+			withUrl(asset.BrowserDownloadUrl),
+			withSys(d.sys))
+	}
+
+	return nil
+}
+
+// replaceAsset deletes the old asset and gives the uploaded asset its name.  If
+// the old asset can't be deleted the uploaded asset is deleted instead.
+func replaceAsset(ctx context.Context, gfs *FS, org, repo string, old, uploaded restAsset) (restAsset, error) {
+	oldUrl := releasesUrl(gfs, org, repo, "assets", strconv.FormatInt(old.Id, 10))
+	newUrl := releasesUrl(gfs, org, repo, "assets", strconv.FormatInt(uploaded.Id, 10))
+
+	if err := restSend(ctx, gfs, http.MethodDelete, oldUrl, "", nil, 0, nil); err != nil {
+		_ = restSend(ctx, gfs, http.MethodDelete, newUrl, "", nil, 0, nil)
+		return restAsset{}, err
+	}
+
+	var asset restAsset
+	input := map[string]string{"name": old.Name}
+	if err := restJSON(ctx, gfs, http.MethodPatch, newUrl, input, &asset); err != nil {
+		return restAsset{}, fmt.Errorf("the asset was uploaded as %s, but couldn't be renamed: %w", uploaded.Name, err)
+	}
+
+	return asset, nil
+}
+
+// getRelease fetches the release for the tag.  Draft releases can't be found
+// by their tag, so the list of releases is searched for them.
+func getRelease(ctx context.Context, gfs *FS, org, repo, tag string) (restRelease, error) {
+	var rel restRelease
+	err := restGet(ctx, gfs, releasesUrl(gfs, org, repo, "tags", url.PathEscape(tag)), &rel)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return rel, err
+	}
+
+	var found bool
+	err = restPages(gfs, releasesUrl(gfs, org, repo), "", func(list []restRelease) error {
+		for _, r := range list {
+			if !found && r.TagName == tag {
+				rel = r
+				found = true
+			}
+		}
+		return nil
+	})
+	if err == nil && !found {
+		err = fmt.Errorf("release %s not found %w", tag, fs.ErrNotExist)
+	}
+
+	return rel, err
+}
+
+// refetchReleases makes the releases directory of the repo read the releases
+// again so a newly created release is included.  Github decides the commit of
+// the tag and which release is the latest, so they are read back rather than
+// guessed.
+func (gfs *FS) refetchReleases(org, repo string) {
+	r := gfs.root.peek(org, repo)
+	if r == nil {
+		return
+	}
+
+	if d := r.peek(dirNameReleases); d != nil {
+		d.children = make(map[string]any)
+		d.fetchFn = getReleaseDir
+		return
+	}
+
+	r.mkdir(dirNameReleases, withFetcher(getReleaseDir), notInPath())
+}
+
+// newReleaseDir makes the directory of a release with the description,
+// metadata and source archives of the release.
+func newReleaseDir(parent *dir, meta releaseMetadata, description string) (*dir, error) {
+	buf, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ReleaseInfo{
+		Tag:        meta.Tag,
+		Draft:      meta.Draft,
+		Prerelease: meta.Prerelease,
+	}
+	modTime := meta.modTime()
+
+	relDir := parent.newDir(meta.Tag, withDirSys(info), withDirModTime(modTime))
+	relDir.addFile(releaseDescription,
+		withContent([]byte(description)),
+		withModTime(modTime),
+		withSys(info))
+	relDir.addFile(releaseMetadataFile,
+		withContent(buf),
+		withModTime(modTime),
+		withSys(info))
+
+	// The source archives are made by github when they are downloaded, so
+	// their size isn't known until then.  Assets with the same names replace
+	// them.
+	if len(meta.TarballUrl) > 0 {
+		relDir.addFile(releaseSourceTarball,
+			withUrl(meta.TarballUrl),
+			withUnknownSize(),
+			withModTime(modTime),
+			withSys(info))
+	}
+	if len(meta.ZipballUrl) > 0 {
+		relDir.addFile(releaseSourceZip,
+			withUrl(meta.ZipballUrl),
+			withUnknownSize(),
+			withModTime(modTime),
+			withSys(info))
+	}
+
+	return relDir, nil
+}
+
+// knownRelease returns the directory of the release if the releases
+// directory of the repo has already been fetched and the release is included,
+// or nil.
+func (gfs *FS) knownRelease(org, repo string, rel restRelease) *dir {
+	d := gfs.root.peek(org, repo, dirNameReleases)
+	if d == nil || d.fetchFn != nil {
+		return nil
+	}
+
+	parent := releaseParent(gfs, d, rel.TagName, rel.Draft, rel.Prerelease)
+	if parent == nil {
+		return nil
+	}
+
+	return parent.peek(rel.TagName)
+}
+
+// releasesUrl returns the REST url of the releases of the repo, or of the
+// parts under it.
+func releasesUrl(gfs *FS, org, repo string, parts ...string) string {
+	return strings.Join(append([]string{gfs.restUrl, "repos", org, repo, "releases"}, parts...), "/")
+}
+
+// assetUploadUrl expands the upload url template of a release, like
+// https://uploads.github.com/repos/org/repo/releases/1/assets{?name,label},
+// for the named asset.
+func assetUploadUrl(tmpl, name string) string {
+	if i := strings.Index(tmpl, "{"); i >= 0 {
+		tmpl = tmpl[:i]
+	}
+	return tmpl + "?name=" + url.QueryEscape(name)
+}
+
+// assetContentType picks the media type of an asset from the extension of
+// its name, or by sniffing the start of the contents if the extension isn't
+// known.
+func assetContentType(name string, r *bufio.Reader) string {
+	if ct := mime.TypeByExtension(path.Ext(name)); len(ct) > 0 {
+		return ct
+	}
+
+	head, _ := r.Peek(512)
+	return http.DetectContentType(head)
+}
+
+// getReleaseDir fetches the release information and makes it into a directory
+// structure that is linked to the filesystem
+func getReleaseDir(gfs *FS, d *dir) error {
+	vars := map[string]any{
+		"owner": d.org,
+		"repo":  d.repo,
+		"count": releasePageSize(gfs.maxReleases, 0),
+		"after": (*string)(nil),
+	}
+
+	if gfs.releases&ReleasesSeparated != 0 {
+		if gfs.releases&ReleasePrereleases != 0 {
+			d.mkdir(dirNamePrerelease, notInPath())
+		}
+		if gfs.releases&ReleaseDrafts != 0 {
+			d.mkdir(dirNameDraft, notInPath())
+		}
+	}
+
+	/*	query MyQuery {
+		  repository(name: "repo", owner: "org") {
+		    releases(first: 10, orderBy: {field: CREATED_AT, direction: DESC}) {
+		      edges {
+		        node {
+		          tagName
+		          name
+		          author {
+		            login
+		          }
+		          tagCommit {
+		            oid
+		          }
+		          isPrerelease
+		          isDraft
+		          isLatest
+		          url
+		          tarballUrl
+		          zipballUrl
+		          createdAt
+		          publishedAt
+		          description
+		          releaseAssets(first: 10) {
+		            pageInfo {
+		              hasNextPage
+		              endCursor
+		            }
+		            edges {
+		              node {
+		                downloadUrl
+		                name
+		                size
+		              }
+		            }
+		          }
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	var read int
+	more := true
+	for more {
+		var query struct {
+			Repository struct {
+				Releases struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+					Edges []struct {
+						Node struct {
+							TagName string
+							Name    string
+							Author  struct {
+								Login string
+							}
+							TagCommit struct {
+								Oid string
+							}
+							IsPrerelease  bool
+							IsDraft       bool
+							IsLatest      bool
+							Url           string
+							TarballUrl    string
+							ZipballUrl    string
+							CreatedAt     time.Time
+							PublishedAt   *time.Time
+							Description   string
+							ReleaseAssets releaseAssets `graphql:"releaseAssets(first:100)"`
+						}
+					}
+				} `graphql:"releases(first: $count, orderBy: {field: CREATED_AT, direction: DESC}, after: $after)"`
+			} `graphql:"repository(name: $repo, owner: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return err
+		}
+
+		for _, edge := range query.Repository.Releases.Edges {
+			rel := edge.Node
+			parent := releaseParent(gfs, d, rel.TagName, rel.IsDraft, rel.IsPrerelease)
+			if parent == nil {
+				continue
+			}
+
+			relDir, err := newReleaseDir(parent, releaseMetadata{
+				Tag:         rel.TagName,
+				Name:        rel.Name,
+				Author:      rel.Author.Login,
+				Commit:      rel.TagCommit.Oid,
+				Draft:       rel.IsDraft,
+				Prerelease:  rel.IsPrerelease,
+				Latest:      rel.IsLatest,
+				Url:         rel.Url,
+				TarballUrl:  rel.TarballUrl,
+				ZipballUrl:  rel.ZipballUrl,
+				CreatedAt:   rel.CreatedAt,
+				PublishedAt: rel.PublishedAt,
+			}, rel.Description)
+			if err != nil {
+				return err
+			}
+
+			if err := addReleaseAssets(gfs, relDir, rel.ReleaseAssets); err != nil {
+				return err
+			}
+		}
+
+		read += len(query.Repository.Releases.Edges)
+		more = query.Repository.Releases.PageInfo.HasNextPage
+		if gfs.maxReleases > 0 && read >= gfs.maxReleases {
+			more = false
+		}
+		vars["count"] = releasePageSize(gfs.maxReleases, read)
+		vars["after"] = query.Repository.Releases.PageInfo.EndCursor
+	}
+
+	updateLatest(d)
+
+	return nil
+}
+
+// releasePageSize returns the number of releases to ask for in the next page
+// so no more than the maximum are read.
+func releasePageSize(max, read int) int {
+	if max > 0 && max-read < 100 {
+		return max - read
+	}
+	return 100
+}
+
+// releaseAssets is a page of the assets of a release.
+type releaseAssets struct {
+	PageInfo struct {
+		HasNextPage bool
+		EndCursor   string
+	}
+	Edges []struct {
+		Node struct {
+			DownloadUrl string
+			Name        string
+			Size        int
+		}
+	}
+}
+
+// addReleaseAssets adds the assets to the directory of the release, fetching
+// the rest of the pages of assets if there are more.
+func addReleaseAssets(gfs *FS, d *dir, assets releaseAssets) error {
+	vars := map[string]any{
+		"owner": d.org,
+		"repo":  d.repo,
+		"tag":   d.name,
+		"after": assets.PageInfo.EndCursor,
+	}
+
+	for {
+		for _, asset := range assets.Edges {
+			d.addFile(asset.Node.Name,
+				withSize(asset.Node.Size),
+				withUrl(asset.Node.DownloadUrl),
+				withSys(d.sys))
+		}
+
+		if !assets.PageInfo.HasNextPage {
+			return nil
+		}
+
+		/*
+			query {
+			  repository(name: "repo", owner: "org") {
+			    release(tagName: "v1.0.0") {
+			      releaseAssets(first: 100, after: "Y3Vyc29y") {
+			        pageInfo {
+			          hasNextPage
+			          endCursor
+			        }
+			        edges {
+			          node {
+			            downloadUrl
+			            name
+			            size
+			          }
+			        }
+			      }
+			    }
+			  }
+			}
+		*/
+		var query struct {
+			Repository struct {
+				Release struct {
+					ReleaseAssets releaseAssets `graphql:"releaseAssets(first: 100, after: $after)"`
+				} `graphql:"release(tagName: $tag)"`
+			} `graphql:"repository(name: $repo, owner: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return err
+		}
+
+		assets = query.Repository.Release.ReleaseAssets
+		vars["after"] = assets.PageInfo.EndCursor
+	}
+}
+
+// releaseParent returns the directory a release belongs in based on the
+// release filter, or nil if the release isn't included.  Drafts that are
+// marked as prereleases are treated as drafts.
+func releaseParent(gfs *FS, d *dir, tag string, draft, prerelease bool) *dir {
+	if len(gfs.constraint) > 0 {
+		v, ok := parseSemver(tag, false)
+		if !ok || !gfs.constraint.allows(v) {
+			return nil
+		}
+	}
+
+	var name string
+	switch {
+	case draft:
+		if gfs.releases&ReleaseDrafts == 0 {
+			return nil
+		}
+		name = dirNameDraft
+	case prerelease:
+		if gfs.releases&ReleasePrereleases == 0 {
+			return nil
+		}
+		name = dirNamePrerelease
+	default:
+		return d
+	}
+
+	if gfs.releases&ReleasesSeparated == 0 {
+		return d
+	}
+	return d.mkdir(name, notInPath())
+}
+
+// updateLatest points the latest directory at the release with the highest
+// stable version.  The latest directory shares the files of the release, so
+// it reads like a symlink.  A release tagged "latest" is left alone.
+func updateLatest(d *dir) {
+	if existing, ok := d.children[dirNameLatest].(*dir); ok {
+		if info, ok := existing.sys.(*ReleaseInfo); !ok || info.Tag == dirNameLatest {
+			return
+		}
+	}
+
+	var best *dir
+	var bestVersion semver
+	for _, child := range d.children {
+		rel, ok := child.(*dir)
+		if !ok || rel.name == dirNameLatest {
+			continue
+		}
+		info, ok := rel.sys.(*ReleaseInfo)
+		if !ok || info.Draft || info.Prerelease {
+			continue
+		}
+		v, ok := parseSemver(info.Tag, false)
+		if !ok || len(v.pre) > 0 {
+			continue
+		}
+		if best == nil || v.compare(bestVersion) > 0 {
+			best, bestVersion = rel, v
+		}
+	}
+
+	if best == nil {
+		delete(d.children, dirNameLatest)
+		return
+	}
+
+	latest := d.newDir(dirNameLatest,
+		withDirSys(best.sys),
+		withDirModTime(best.modTime))
+	latest.children = best.children
+}
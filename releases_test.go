@@ -0,0 +1,972 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRelease(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, requests, bodies := newTestFS(t, fsTest{
+		opts: []Option{WithRepo("org", "repo")},
+		payload: []string{
+			singleRepoWithReleasesReponse,
+			releaseResponse,
+			createReleaseResponse,
+			createdReleaseResponse,
+		},
+	})
+
+	entries, err := fs.ReadDir(gfs, "org/repo/releases")
+	require.NoError(err)
+	require.Len(entries, 2)
+
+	err = gfs.CreateRelease(context.Background(), "org", "repo", "v1.0.0", ReleaseOptions{
+		Body:   "Release notes",
+		Target: "main",
+	})
+	require.NoError(err)
+
+	// The releases are read again with the details github filled in.
+	entries, err = fs.ReadDir(gfs, "org/repo/releases")
+	require.NoError(err)
+	assert.Len(entries, 3)
+
+	got, err := fs.ReadFile(gfs, "org/repo/releases/v1.0.0/description.md")
+	require.NoError(err)
+	assert.Equal("Release notes", string(got))
+
+	got, err = fs.ReadFile(gfs, "org/repo/releases/v1.0.0/release.json")
+	require.NoError(err)
+	assert.Contains(string(got), `"commit": "1111111111111111111111111111111111111111"`)
+	assert.Contains(string(got), `"latest": true`)
+
+	got, err = fs.ReadFile(gfs, "org/repo/releases/v0.6.7/release.json")
+	require.NoError(err)
+	assert.Contains(string(got), `"latest": false`)
+
+	// The new release is the latest.
+	got, err = fs.ReadFile(gfs, "org/repo/releases/latest/description.md")
+	require.NoError(err)
+	assert.Equal("Release notes", string(got))
+
+	assert.Equal("POST /repos/org/repo/releases", (*requests)[2])
+	assert.Equal(`{"tag_name":"v1.0.0","target_commitish":"main","body":"Release notes","draft":false,"prerelease":false}`, (*bodies)[2])
+	assert.Equal("POST /", (*requests)[3])
+}
+
+func TestCreateReleaseErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	gfs, _, _ := newTestFS(t, fsTest{
+		opts:       []Option{WithRepo("org", "repo")},
+		payload:    []string{singleRepoReponse, `{"message":"Validation Failed"}`},
+		statusCode: []int{0, 422},
+	})
+
+	err := gfs.CreateRelease(context.Background(), "org", "repo", "", ReleaseOptions{})
+	assert.ErrorIs(err, fs.ErrInvalid)
+
+	err = gfs.CreateRelease(context.Background(), "org", "repo", "v1.0.0", ReleaseOptions{})
+	assert.Error(err)
+}
+
+func TestWriteRelease(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, requests, bodies := newTestFS(t, fsTest{
+		opts: []Option{WithRepo("org", "repo")},
+		payload: []string{
+			singleRepoWithReleasesReponse,
+			releaseResponse,
+			restReleaseResponse,
+			uploadTempAssetResponse,
+			"",
+			uploadAssetResponse,
+			restReleaseResponse,
+			updatedReleaseResponse,
+		},
+	})
+
+	_, err := fs.ReadDir(gfs, "org/repo/releases")
+	require.NoError(err)
+
+	data := []byte("0123456789abcdef  talaria-0.6.7.tar.gz\n")
+	require.NoError(gfs.WriteFile("org/repo/releases/v0.6.7/sha256sum.txt", data))
+
+	entries, err := fs.ReadDir(gfs, "org/repo/releases/v0.6.7")
+	require.NoError(err)
+	for _, entry := range entries {
+		if entry.Name() == "sha256sum.txt" {
+			info, err := entry.Info()
+			require.NoError(err)
+			assert.Equal(int64(len(data)), info.Size())
+		}
+	}
+
+	require.NoError(gfs.WriteFile("org/repo/releases/v0.6.7/description.md", []byte("New notes")))
+
+	got, err := fs.ReadFile(gfs, "org/repo/releases/v0.6.7/description.md")
+	require.NoError(err)
+	assert.Equal("New notes", string(got))
+
+	assert.Equal([]string{
+		"POST /",
+		"POST /",
+		"GET /repos/org/repo/releases/tags/v0.6.7",
+		"POST /repos/org/repo/releases/1/assets?name=sha256sum.txt.5.tmp",
+		"DELETE /repos/org/repo/releases/assets/5",
+		"PATCH /repos/org/repo/releases/assets/6",
+		"GET /repos/org/repo/releases/tags/v0.6.7",
+		"PATCH /repos/org/repo/releases/1",
+	}, *requests)
+	assert.Equal(string(data), (*bodies)[3])
+	assert.Equal(`{"name":"sha256sum.txt"}`, (*bodies)[5])
+	assert.Equal(`{"body":"New notes"}`, (*bodies)[7])
+}
+
+func TestReplaceAssetErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		payload     []string
+		statusCode  []int
+		requests    []string
+	}{
+		{
+			description: "the upload fails and the old asset is kept",
+			payload:     []string{singleRepoReponse, restReleaseResponse},
+			statusCode:  []int{0, 0, 500},
+			requests: []string{
+				"POST /",
+				"GET /repos/org/repo/releases/tags/v0.6.7",
+				"POST /repos/org/repo/releases/1/assets?name=sha256sum.txt.5.tmp",
+			},
+		}, {
+			description: "the old asset can't be deleted so the new one is",
+			payload:     []string{singleRepoReponse, restReleaseResponse, uploadTempAssetResponse, "", ""},
+			statusCode:  []int{0, 0, 0, 500, 0},
+			requests: []string{
+				"POST /",
+				"GET /repos/org/repo/releases/tags/v0.6.7",
+				"POST /repos/org/repo/releases/1/assets?name=sha256sum.txt.5.tmp",
+				"DELETE /repos/org/repo/releases/assets/5",
+				"DELETE /repos/org/repo/releases/assets/6",
+			},
+		}, {
+			description: "the new asset can't be renamed",
+			payload:     []string{singleRepoReponse, restReleaseResponse, uploadTempAssetResponse, "", ""},
+			statusCode:  []int{0, 0, 0, 0, 500},
+			requests: []string{
+				"POST /",
+				"GET /repos/org/repo/releases/tags/v0.6.7",
+				"POST /repos/org/repo/releases/1/assets?name=sha256sum.txt.5.tmp",
+				"DELETE /repos/org/repo/releases/assets/5",
+				"PATCH /repos/org/repo/releases/assets/6",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gfs, requests, _ := newTestFS(t, fsTest{
+				opts:       []Option{WithRepo("org", "repo")},
+				payload:    tc.payload,
+				statusCode: tc.statusCode,
+			})
+
+			data := "0123456789abcdef  talaria-0.6.7.tar.gz\n"
+			err := gfs.UploadAsset(context.Background(), "org/repo/releases/v0.6.7/sha256sum.txt",
+				strings.NewReader(data), int64(len(data)))
+			assert.Error(err)
+			assert.Equal(tc.requests, *requests)
+		})
+	}
+}
+
+func TestUploadEmptyAsset(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(int64(0), r.ContentLength)
+		assert.Empty(r.TransferEncoding)
+		fmt.Fprint(w, `{"id":7,"name":"empty.txt","size":0}`)
+	}))
+	defer server.Close()
+
+	var asset restAsset
+	err := restSend(context.Background(), New(), http.MethodPost, server.URL, "text/plain",
+		bufio.NewReader(strings.NewReader("")), 0, &asset)
+	require.NoError(err)
+	assert.Equal("empty.txt", asset.Name)
+}
+
+func TestUploadAssetToDraft(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, requests, _ := newTestFS(t, fsTest{
+		opts: []Option{WithRepo("org", "repo")},
+		payload: []string{
+			singleRepoReponse,
+			`{"message":"Not Found"}`,
+			"[" + restReleaseResponse + "]",
+			uploadAssetResponse,
+		},
+		statusCode: []int{0, 404, 0, 0},
+	})
+
+	data := "0123456789abcdef  talaria-0.6.7.tar.gz\n"
+	err := gfs.UploadAsset(context.Background(), "org/repo/releases/v0.6.7/app.bin", strings.NewReader(data), int64(len(data)))
+	require.NoError(err)
+
+	assert.Equal("GET /repos/org/repo/releases?per_page=100&page=1", (*requests)[2])
+	assert.Equal("POST /repos/org/repo/releases/1/assets?name=app.bin", (*requests)[3])
+}
+
+func TestUploadAssetErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		name        string
+		size        int64
+		errIs       error
+	}{
+		{
+			description: "invalid path",
+			name:        "/org/repo/releases/v1/a.txt",
+			errIs:       fs.ErrInvalid,
+		}, {
+			description: "negative size",
+			name:        "org/repo/releases/v1/a.txt",
+			size:        -1,
+			errIs:       fs.ErrInvalid,
+		}, {
+			description: "not in a release",
+			name:        "org/repo/git/main/a.txt",
+			errIs:       fs.ErrPermission,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			gfs := New()
+			err := gfs.UploadAsset(context.Background(), tc.name, strings.NewReader(""), tc.size)
+			assert.ErrorIs(t, err, tc.errIs)
+		})
+	}
+}
+
+func TestAssetContentType(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		expect   string
+	}{
+		{name: "app.json", contents: "{}", expect: "application/json"},
+		{name: "app.bin.unknown", contents: "\x1f\x8b\x08\x00", expect: "application/x-gzip"},
+		{name: "notes", contents: "plain words", expect: "text/plain; charset=utf-8"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tc.contents))
+			assert.Equal(t, tc.expect, assetContentType(tc.name, r))
+
+			// The sniffed bytes are still read.
+			rest, _ := r.ReadString(0)
+			assert.Equal(t, tc.contents, rest)
+		})
+	}
+}
+
+func TestAssetUploadUrl(t *testing.T) {
+	assert.Equal(t, "https://uploads.github.com/repos/org/repo/releases/1/assets?name=my+app.zip",
+		assetUploadUrl("https://uploads.github.com/repos/org/repo/releases/1/assets{?name,label}", "my app.zip"))
+}
+
+func TestReleaseFilter(t *testing.T) {
+	tests := []struct {
+		description string
+		filters     []ReleaseFilter
+		expect      []string
+		unexpected  []string
+	}{
+		{
+			description: "only stable releases",
+			expect:      []string{"v1.0.0"},
+			unexpected:  []string{"v1.1.0-rc1", "v1.1.0", "draft", "prerelease"},
+		}, {
+			description: "prereleases mixed in",
+			filters:     []ReleaseFilter{ReleasePrereleases},
+			expect:      []string{"v1.0.0", "v1.1.0-rc1"},
+			unexpected:  []string{"v1.1.0", "draft", "prerelease"},
+		}, {
+			description: "everything mixed in",
+			filters:     []ReleaseFilter{ReleasePrereleases, ReleaseDrafts},
+			expect:      []string{"v1.0.0", "v1.1.0-rc1", "v1.1.0"},
+			unexpected:  []string{"draft", "prerelease"},
+		}, {
+			description: "everything separated",
+			filters:     []ReleaseFilter{ReleasePrereleases, ReleaseDrafts, ReleasesSeparated},
+			expect:      []string{"v1.0.0", "prerelease/v1.1.0-rc1", "draft/v1.1.0"},
+			unexpected:  []string{"v1.1.0-rc1", "v1.1.0"},
+		}, {
+			description: "drafts separated",
+			filters:     []ReleaseFilter{ReleaseDrafts, ReleasesSeparated},
+			expect:      []string{"v1.0.0", "draft/v1.1.0"},
+			unexpected:  []string{"prerelease", "v1.1.0-rc1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			gfs, _, _ := newTestFS(t, fsTest{
+				opts:    []Option{WithRepo("org", "repo"), WithReleaseFilter(tc.filters...)},
+				payload: []string{singleRepoWithReleasesReponse, filterReleaseResponse},
+			})
+
+			for _, name := range tc.expect {
+				info, err := fs.Stat(gfs, "org/repo/releases/"+name)
+				require.NoError(err, name)
+				assert.True(info.IsDir())
+
+				ri, ok := info.Sys().(*ReleaseInfo)
+				require.True(ok)
+				assert.Equal(path.Base(name) == "v1.1.0", ri.Draft)
+				assert.Equal(path.Base(name), ri.Tag)
+			}
+			for _, name := range tc.unexpected {
+				_, err := fs.Stat(gfs, "org/repo/releases/"+name)
+				assert.ErrorIs(err, fs.ErrNotExist, name)
+			}
+		})
+	}
+}
+
+func TestReleaseInfoOnFiles(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, _, _ := newTestFS(t, fsTest{
+		opts:    []Option{WithRepo("org", "repo"), WithReleaseFilter(ReleasePrereleases)},
+		payload: []string{singleRepoWithReleasesReponse, filterReleaseResponse},
+	})
+
+	info, err := fs.Stat(gfs, "org/repo/releases/v1.1.0-rc1/description.md")
+	require.NoError(err)
+	assert.Equal(&ReleaseInfo{Tag: "v1.1.0-rc1", Prerelease: true}, info.Sys())
+}
+
+func TestReleasePath(t *testing.T) {
+	tests := []struct {
+		name   string
+		tag    string
+		file   string
+		expect bool
+	}{
+		{name: "org/repo/releases/v1/a.txt", tag: "v1", file: "a.txt", expect: true},
+		{name: "org/repo/releases/draft/v1/a.txt", tag: "v1", file: "a.txt", expect: true},
+		{name: "org/repo/releases/prerelease/v1/a.txt", tag: "v1", file: "a.txt", expect: true},
+		{name: "org/repo/releases/other/v1/a.txt"},
+		{name: "org/repo/releases/v1"},
+		{name: "org/repo/git/main/a.txt"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			org, repo, tag, file, ok := releasePath(tc.name)
+			assert.Equal(t, tc.expect, ok)
+			if tc.expect {
+				assert.Equal(t, "org", org)
+				assert.Equal(t, "repo", repo)
+				assert.Equal(t, tc.tag, tag)
+				assert.Equal(t, tc.file, file)
+			}
+		})
+	}
+}
+
+var filterReleaseResponse = `{
+  "data": {
+    "repository": {
+      "releases": {
+        "edges": [
+          {
+            "node": {
+              "tagName": "v1.1.0",
+              "isPrerelease": false,
+              "isDraft": true,
+              "createdAt": "2022-09-07T20:21:35Z",
+              "description": "Draft",
+              "releaseAssets": {
+                "edges": []
+              }
+            }
+          },
+          {
+            "node": {
+              "tagName": "v1.1.0-rc1",
+              "isPrerelease": true,
+              "isDraft": false,
+              "createdAt": "2022-09-06T20:21:35Z",
+              "description": "Release candidate",
+              "releaseAssets": {
+                "edges": []
+              }
+            }
+          },
+          {
+            "node": {
+              "tagName": "v1.0.0",
+              "isPrerelease": false,
+              "isDraft": false,
+              "createdAt": "2022-08-26T22:53:33Z",
+              "description": "Stable",
+              "releaseAssets": {
+                "edges": []
+              }
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+func TestReleaseConstraint(t *testing.T) {
+	tests := []struct {
+		description string
+		opts        []Option
+		expect      []string
+		unexpected  []string
+		latest      string
+		expectErr   bool
+	}{
+		{
+			description: "every release",
+			expect:      []string{"nightly", "v1.2.0", "v1.9.1", "v1.10.0", "v2.0.0"},
+			latest:      "v2.0.0",
+		}, {
+			description: "only v1",
+			opts:        []Option{WithReleaseConstraint(">=1, <2")},
+			expect:      []string{"v1.2.0", "v1.9.1", "v1.10.0"},
+			unexpected:  []string{"nightly", "v2.0.0"},
+			latest:      "v1.10.0",
+		}, {
+			description: "prereleases are never the latest",
+			opts:        []Option{WithReleaseFilter(ReleasePrereleases)},
+			expect:      []string{"v3.0.0-rc.1"},
+			latest:      "v2.0.0",
+		}, {
+			description: "nothing matches",
+			opts:        []Option{WithReleaseConstraint(">=5")},
+			unexpected:  []string{"latest", "v2.0.0"},
+		}, {
+			description: "invalid constraint",
+			opts:        []Option{WithReleaseConstraint("~1")},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			gfs, requests, _ := newTestFS(t, fsTest{
+				opts:    append([]Option{WithRepo("org", "repo")}, tc.opts...),
+				payload: []string{singleRepoWithReleasesReponse, versionReleaseResponse},
+			})
+
+			_, err := fs.Stat(gfs, "org/repo/releases")
+			if tc.expectErr {
+				// An invalid constraint is found before connecting.
+				assert.Error(err)
+				assert.Empty(*requests)
+				return
+			}
+			require.NoError(err)
+
+			for _, name := range tc.expect {
+				_, err := fs.Stat(gfs, "org/repo/releases/"+name)
+				assert.NoError(err, name)
+			}
+			for _, name := range tc.unexpected {
+				_, err := fs.Stat(gfs, "org/repo/releases/"+name)
+				assert.ErrorIs(err, fs.ErrNotExist, name)
+			}
+
+			if len(tc.latest) > 0 {
+				info, err := fs.Stat(gfs, "org/repo/releases/latest")
+				require.NoError(err)
+				assert.Equal(tc.latest, info.Sys().(*ReleaseInfo).Tag)
+
+				got, err := fs.ReadFile(gfs, "org/repo/releases/latest/description.md")
+				require.NoError(err)
+				assert.Equal(tc.latest, string(got))
+			}
+		})
+	}
+}
+
+var versionReleaseResponse = `{
+  "data": {
+    "repository": {
+      "releases": {
+        "edges": [
+          { "node": { "tagName": "nightly", "description": "nightly", "releaseAssets": { "edges": [] } } },
+          { "node": { "tagName": "v3.0.0-rc.1", "isPrerelease": true, "description": "v3.0.0-rc.1", "releaseAssets": { "edges": [] } } },
+          { "node": { "tagName": "v1.9.1", "description": "v1.9.1", "releaseAssets": { "edges": [] } } },
+          { "node": { "tagName": "v2.0.0", "description": "v2.0.0", "releaseAssets": { "edges": [] } } },
+          { "node": { "tagName": "v1.10.0", "description": "v1.10.0", "releaseAssets": { "edges": [] } } },
+          { "node": { "tagName": "v1.2.0", "description": "v1.2.0", "releaseAssets": { "edges": [] } } }
+        ]
+      }
+    }
+  }
+}`
+
+func TestMaxReleases(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, requests, bodies := newTestFS(t, fsTest{
+		opts: []Option{WithRepo("org", "repo"), WithMaxReleases(2)},
+		payload: []string{
+			singleRepoWithReleasesReponse,
+			pagedReleaseResponse("v1.0.1", "cursor1"),
+			pagedReleaseResponse("v1.0.0", "cursor2"),
+		},
+	})
+
+	_, err := fs.Stat(gfs, "org/repo/releases/v1.0.0")
+	require.NoError(err)
+
+	// The second page has more, but the maximum was read.
+	assert.Len(*requests, 3)
+	assert.Contains((*bodies)[1], `"count":2`)
+	assert.Contains((*bodies)[2], `"count":1`)
+	assert.Contains((*bodies)[2], `"after":"cursor1"`)
+}
+
+func TestReleasePageSize(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(100, releasePageSize(0, 0))
+	assert.Equal(100, releasePageSize(0, 500))
+	assert.Equal(100, releasePageSize(250, 100))
+	assert.Equal(50, releasePageSize(250, 200))
+	assert.Equal(5, releasePageSize(5, 0))
+}
+
+func TestReleaseAssetPages(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, _, bodies := newTestFS(t, fsTest{
+		opts: []Option{WithRepo("org", "repo")},
+		payload: []string{
+			singleRepoWithReleasesReponse,
+			manyAssetsReleaseResponse,
+			moreAssetsResponse,
+		},
+	})
+
+	entries, err := fs.ReadDir(gfs, "org/repo/releases/v1.0.0")
+	require.NoError(err)
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal([]string{"a.tar.gz", "b.tar.gz", "description.md", "release.json"}, names)
+
+	assert.Contains((*bodies)[2], `"after":"assets1"`)
+	assert.Contains((*bodies)[2], `"tag":"v1.0.0"`)
+}
+
+func pagedReleaseResponse(tag, cursor string) string {
+	return `{
+  "data": {
+    "repository": {
+      "releases": {
+        "pageInfo": {
+          "hasNextPage": true,
+          "endCursor": "` + cursor + `"
+        },
+        "edges": [
+          { "node": { "tagName": "` + tag + `", "description": "", "releaseAssets": { "edges": [] } } }
+        ]
+      }
+    }
+  }
+}`
+}
+
+var manyAssetsReleaseResponse = `{
+  "data": {
+    "repository": {
+      "releases": {
+        "edges": [
+          {
+            "node": {
+              "tagName": "v1.0.0",
+              "description": "",
+              "releaseAssets": {
+                "pageInfo": {
+                  "hasNextPage": true,
+                  "endCursor": "assets1"
+                },
+                "edges": [
+                  {
+                    "node": {
+                      "downloadUrl": "https://github.com/org/repo/releases/download/v1.0.0/a.tar.gz",
+                      "name": "a.tar.gz",
+                      "size": 10
+                    }
+                  }
+                ]
+              }
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+var moreAssetsResponse = `{
+  "data": {
+    "repository": {
+      "release": {
+        "releaseAssets": {
+          "pageInfo": {
+            "hasNextPage": false,
+            "endCursor": "assets2"
+          },
+          "edges": [
+            {
+              "node": {
+                "downloadUrl": "https://github.com/org/repo/releases/download/v1.0.0/b.tar.gz",
+                "name": "b.tar.gz",
+                "size": 20
+              }
+            }
+          ]
+        }
+      }
+    }
+  }
+}`
+
+func TestReleaseMetadata(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, _, _ := newTestFS(t, fsTest{
+		opts:    []Option{WithRepo("org", "repo"), WithReleaseFilter(ReleaseDrafts)},
+		payload: []string{singleRepoWithReleasesReponse, metadataReleaseResponse},
+	})
+
+	got, err := fs.ReadFile(gfs, "org/repo/releases/v1.0.0/release.json")
+	require.NoError(err)
+	assert.Equal(`{
+  "tag": "v1.0.0",
+  "name": "The first one",
+  "author": "mona",
+  "commit": "1111111111111111111111111111111111111111",
+  "draft": false,
+  "prerelease": false,
+  "latest": true,
+  "url": "https://github.com/org/repo/releases/tag/v1.0.0",
+  "tarballUrl": "https://api.github.com/repos/org/repo/tarball/v1.0.0",
+  "zipballUrl": "https://api.github.com/repos/org/repo/zipball/v1.0.0",
+  "createdAt": "2022-08-26T22:53:33Z",
+  "publishedAt": "2022-08-27T10:00:00Z"
+}`, string(got))
+
+	published := time.Date(2022, 8, 27, 10, 0, 0, 0, time.UTC)
+	for _, name := range []string{"v1.0.0", "v1.0.0/description.md", "v1.0.0/release.json"} {
+		info, err := fs.Stat(gfs, "org/repo/releases/"+name)
+		require.NoError(err)
+		assert.True(published.Equal(info.ModTime()), name)
+	}
+
+	// Drafts aren't published yet.
+	info, err := fs.Stat(gfs, "org/repo/releases/v1.1.0")
+	require.NoError(err)
+	assert.True(time.Date(2022, 9, 7, 20, 21, 35, 0, time.UTC).Equal(info.ModTime()))
+
+	got, err = fs.ReadFile(gfs, "org/repo/releases/v1.1.0/release.json")
+	require.NoError(err)
+	assert.NotContains(string(got), "publishedAt")
+	assert.NotContains(string(got), "commit")
+}
+
+var metadataReleaseResponse = `{
+  "data": {
+    "repository": {
+      "releases": {
+        "edges": [
+          {
+            "node": {
+              "tagName": "v1.1.0",
+              "name": "Next",
+              "author": {
+                "login": "mona"
+              },
+              "tagCommit": null,
+              "isPrerelease": false,
+              "isDraft": true,
+              "isLatest": false,
+              "url": "https://github.com/org/repo/releases/tag/untagged-1",
+              "createdAt": "2022-09-07T20:21:35Z",
+              "publishedAt": null,
+              "description": "Draft",
+              "releaseAssets": {
+                "edges": []
+              }
+            }
+          },
+          {
+            "node": {
+              "tagName": "v1.0.0",
+              "name": "The first one",
+              "author": {
+                "login": "mona"
+              },
+              "tagCommit": {
+                "oid": "1111111111111111111111111111111111111111"
+              },
+              "isPrerelease": false,
+              "isDraft": false,
+              "isLatest": true,
+              "url": "https://github.com/org/repo/releases/tag/v1.0.0",
+              "tarballUrl": "https://api.github.com/repos/org/repo/tarball/v1.0.0",
+              "zipballUrl": "https://api.github.com/repos/org/repo/zipball/v1.0.0",
+              "createdAt": "2022-08-26T22:53:33Z",
+              "publishedAt": "2022-08-27T10:00:00Z",
+              "description": "Stable",
+              "releaseAssets": {
+                "edges": []
+              }
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+func TestReleaseSource(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, requests, _ := newTestFS(t, fsTest{
+		opts: []Option{WithRepo("org", "repo")},
+		payload: []string{
+			singleRepoWithReleasesReponse,
+			sourceReleaseResponse,
+			"tarball contents",
+			"zipball contents",
+		},
+	})
+
+	entries, err := fs.ReadDir(gfs, "org/repo/releases/v1.0.0")
+	require.NoError(err)
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal([]string{"description.md", "release.json", "source.tar.gz", "source.zip"}, names)
+
+	// Nothing is downloaded until the archives are read.
+	assert.Len(*requests, 2)
+
+	got, err := fs.ReadFile(gfs, "org/repo/releases/v1.0.0/source.tar.gz")
+	require.NoError(err)
+	assert.Equal("tarball contents", string(got))
+
+	info, err := fs.Stat(gfs, "org/repo/releases/v1.0.0/source.zip")
+	require.NoError(err)
+	assert.Equal(int64(len("zipball contents")), info.Size())
+
+	assert.Equal("GET /repos/org/repo/tarball/v1.0.0", (*requests)[2])
+	assert.Equal("GET /repos/org/repo/zipball/v1.0.0", (*requests)[3])
+}
+
+func TestReleaseSourceReplacedByAsset(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, _, _ := newTestFS(t, fsTest{
+		opts: []Option{WithRepo("org", "repo")},
+		payload: []string{
+			singleRepoWithReleasesReponse,
+			strings.Replace(sourceReleaseResponse, `"edges": []`, `"edges": [
+                  {
+                    "node": {
+                      "downloadUrl": "OVERWRITEURL/org/repo/releases/download/v1.0.0/source.zip",
+                      "name": "source.zip",
+                      "size": 5
+                    }
+                  }
+                ]`, 1),
+		},
+	})
+
+	entries, err := fs.ReadDir(gfs, "org/repo/releases/v1.0.0")
+	require.NoError(err)
+
+	for _, entry := range entries {
+		if entry.Name() == "source.zip" {
+			info, err := entry.Info()
+			require.NoError(err)
+			assert.Equal(int64(5), info.Size())
+		}
+	}
+}
+
+var sourceReleaseResponse = `{
+  "data": {
+    "repository": {
+      "releases": {
+        "edges": [
+          {
+            "node": {
+              "tagName": "v1.0.0",
+              "isPrerelease": false,
+              "isDraft": false,
+              "tarballUrl": "OVERWRITEURL/repos/org/repo/tarball/v1.0.0",
+              "zipballUrl": "OVERWRITEURL/repos/org/repo/zipball/v1.0.0",
+              "createdAt": "2022-08-26T22:53:33Z",
+              "description": "Stable",
+              "releaseAssets": {
+                "edges": []
+              }
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+var createReleaseResponse = `{
+  "id": 2,
+  "tag_name": "v1.0.0",
+  "body": "Release notes",
+  "draft": false,
+  "prerelease": false,
+  "upload_url": "OVERWRITEURL/repos/org/repo/releases/2/assets{?name,label}",
+  "assets": []
+}`
+
+var createdReleaseResponse = `{
+  "data": {
+    "repository": {
+      "releases": {
+        "edges": [
+          {
+            "node": {
+              "tagName": "v1.0.0",
+              "tagCommit": {
+                "oid": "1111111111111111111111111111111111111111"
+              },
+              "isPrerelease": false,
+              "isDraft": false,
+              "isLatest": true,
+              "createdAt": "2022-09-07T20:21:35Z",
+              "publishedAt": "2022-09-07T20:21:35Z",
+              "description": "Release notes",
+              "releaseAssets": {
+                "edges": []
+              }
+            }
+          },
+          {
+            "node": {
+              "tagName": "v0.6.7",
+              "tagCommit": {
+                "oid": "2222222222222222222222222222222222222222"
+              },
+              "isPrerelease": false,
+              "isDraft": false,
+              "isLatest": false,
+              "createdAt": "2022-08-26T22:53:33Z",
+              "publishedAt": "2022-08-26T22:53:33Z",
+              "description": "Old notes",
+              "releaseAssets": {
+                "edges": []
+              }
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+var restReleaseResponse = `{
+  "id": 1,
+  "tag_name": "v0.6.7",
+  "body": "Old notes",
+  "draft": false,
+  "prerelease": false,
+  "upload_url": "OVERWRITEURL/repos/org/repo/releases/1/assets{?name,label}",
+  "assets": [
+    {
+      "id": 5,
+      "name": "sha256sum.txt",
+      "size": 171,
+      "browser_download_url": "OVERWRITEURL/org/repo/releases/download/v0.6.7/sha256sum.txt"
+    }
+  ]
+}`
+
+var updatedReleaseResponse = `{
+  "id": 1,
+  "tag_name": "v0.6.7",
+  "body": "New notes",
+  "draft": false,
+  "prerelease": false,
+  "upload_url": "OVERWRITEURL/repos/org/repo/releases/1/assets{?name,label}",
+  "assets": []
+}`
+
+var uploadTempAssetResponse = `{
+  "id": 6,
+  "name": "sha256sum.txt.5.tmp",
+  "size": 39,
+  "browser_download_url": "OVERWRITEURL/org/repo/releases/download/v0.6.7/sha256sum.txt.5.tmp"
+}`
+
+var uploadAssetResponse = `{
+  "id": 6,
+  "name": "sha256sum.txt",
+  "size": 39,
+  "browser_download_url": "OVERWRITEURL/org/repo/releases/download/v0.6.7/sha256sum.txt"
+}`
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// mediaTypeRest is the media type for the github REST API.
+const mediaTypeRest = "application/vnd.github+json"
+
+// download fetches the url and returns the body.  The accept media type is
+// optional.
+func download(ctx context.Context, gfs *FS, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(accept) > 0 {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := gfs.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("http status code not 200: %d %w", resp.StatusCode, fs.ErrNotExist)
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("http status code not 200: %d %w", resp.StatusCode, fs.ErrPermission)
+	default:
+		return nil, fmt.Errorf("http status code not 200: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// restGet calls the github REST API and decodes the json response into v.
+func restGet(ctx context.Context, gfs *FS, url string, v any) error {
+	buf, err := download(ctx, gfs, url, mediaTypeRest)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(buf, v)
+}
+
+// restPages calls fn with each page of a REST API list until a partial page
+// is returned.  Most lists are returned as an object with the list under a
+// key like "workflow_runs", others are returned as the list itself when the
+// key is empty.
+func restPages[T any](gfs *FS, u, key string, fn func([]T) error) error {
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+
+	for page := 1; ; page++ {
+		var list []T
+		pageUrl := u + sep + "per_page=100&page=" + strconv.Itoa(page)
+		if len(key) == 0 {
+			if err := restGet(context.Background(), gfs, pageUrl, &list); err != nil {
+				return err
+			}
+		} else {
+			var obj map[string]json.RawMessage
+			if err := restGet(context.Background(), gfs, pageUrl, &obj); err != nil {
+				return err
+			}
+			if raw, found := obj[key]; found {
+				if err := json.Unmarshal(raw, &list); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := fn(list); err != nil {
+			return err
+		}
+
+		if len(list) < 100 {
+			return nil
+		}
+	}
+}
+
+// restSend sends the body to the github REST API with the method and decodes
+// the json response into v unless v is nil.  The size of the body must be
+// known since github requires it for uploads.
+func restSend(ctx context.Context, gfs *FS, method, url, contentType string, body io.Reader, size int64, v any) error {
+	if size == 0 {
+		// Otherwise a body that isn't nil is sent chunked.
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Accept", mediaTypeRest)
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := gfs.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("http status code not 2xx: %d %w", resp.StatusCode, fs.ErrNotExist)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("http status code not 2xx: %d", resp.StatusCode)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// restJSON sends the json encoding of in to the github REST API with the
+// method and decodes the json response into out unless out is nil.
+func restJSON(ctx context.Context, gfs *FS, method, url string, in, out any) error {
+	buf, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	return restSend(ctx, gfs, method, url, "application/json", bytes.NewReader(buf), int64(len(buf)), out)
+}
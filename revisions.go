@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// Revision is a commit that changed a file.
+type Revision struct {
+	// Sha is the full commit sha.
+	Sha string
+
+	// Message is the commit message.
+	Message string
+
+	// Author is the github login of the author if known, otherwise the name
+	// of the author.
+	Author string
+
+	// Date is when the commit was committed.
+	Date time.Time
+}
+
+// Revisions returns the commits that changed the file at the path, newest
+// first.  The path must be in a tree like org/repo/git/main/README.md, and
+// the history starts at the commit the tree is read at.
+//
+// The file can be read at any of the revisions by appending @ and the sha to
+// the path like this:
+//
+// org/repo/git/main/README.md@4b825dc642cb6eb9a060e54bf8d69288fbee4904
+func (gfs *FS) Revisions(name string) ([]Revision, error) {
+	if !fs.ValidPath(name) {
+		return nil, fmt.Errorf("revisions %s %w", name, fs.ErrInvalid)
+	}
+
+	if err := gfs.connect(); err != nil {
+		return nil, fmt.Errorf("revisions %s error connecting: %w", name, err)
+	}
+
+	tree, rel, err := gfs.findTree(name)
+	if err != nil {
+		return nil, fmt.Errorf("revisions %s %w", name, err)
+	}
+
+	vars := map[string]any{
+		"owner": tree.org,
+		"repo":  tree.repo,
+		"exp":   tree.rev(),
+		"path":  rel,
+		"count": 100,
+		"after": (*string)(nil),
+	}
+
+	/*
+		query {
+		  repository(name: "repo", owner: "org") {
+		    object(expression: "main") {
+		      ... on Commit {
+		        history(first: 100, path: "README.md") {
+		          edges {
+		            node {
+		              oid
+		              message
+		              committedDate
+		              author {
+		                name
+		                user {
+		                  login
+		                }
+		              }
+		            }
+		          }
+		        }
+		      }
+		    }
+		  }
+		}
+	*/
+	var revs []Revision
+	more := true
+	for more {
+		var query struct {
+			Repository struct {
+				Object struct {
+					Commit struct {
+						History struct {
+							PageInfo struct {
+								HasNextPage bool
+								EndCursor   string
+							}
+							Edges []struct {
+								Node struct {
+									Oid           string
+									Message       string
+									CommittedDate time.Time
+									Author        gitActor
+								}
+							}
+						} `graphql:"history(first: $count, after: $after, path: $path)"`
+					} `graphql:"... on Commit"`
+				} `graphql:"object(expression: $exp)"`
+			} `graphql:"repository(name: $repo, owner: $owner)"`
+		}
+
+		if err := gfs.gqlClient.Query(context.Background(), &query, vars); err != nil {
+			return nil, fmt.Errorf("revisions %s %w", name, err)
+		}
+
+		history := query.Repository.Object.Commit.History
+		for _, edge := range history.Edges {
+			revs = append(revs, Revision{
+				Sha:     edge.Node.Oid,
+				Message: edge.Node.Message,
+				Author:  edge.Node.Author.login(),
+				Date:    edge.Node.CommittedDate,
+			})
+		}
+
+		more = history.PageInfo.HasNextPage
+		vars["after"] = history.PageInfo.EndCursor
+	}
+
+	return revs, nil
+}
+
+// findTree finds the directory of the git tree the path is in and returns it
+// with the rest of the path.  Only the trees of the branches, tags, commits
+// and pull requests count, not the other directories pinned to a commit like
+// the history.  The tree itself isn't fetched.
+func (gfs *FS) findTree(name string) (*dir, string, error) {
+	parts := strings.Split(name, "/")
+	cur := gfs.root
+	for i, part := range parts {
+		if len(cur.rev()) > 0 {
+			if !cur.tree {
+				break
+			}
+			return cur, strings.Join(parts[i:], "/"), nil
+		}
+
+		child, err := cur.child(part)
+		if err != nil {
+			return nil, "", err
+		}
+		next, ok := child.(*dir)
+		if !ok {
+			break
+		}
+		cur = next
+	}
+
+	return nil, "", fmt.Errorf("%s is not a file in a git tree %w", name, fs.ErrNotExist)
+}
+
+// splitRevision splits a name like "README.md@4b825dc" into the path and the
+// full or abbreviated sha.
+func splitRevision(name string) (string, string, bool) {
+	i := strings.LastIndex(name, "@")
+	if i < 1 || !isSha(name[i+1:]) {
+		return "", "", false
+	}
+	return name[:i], name[i+1:], true
+}
+
+// openRevision opens the file at the path as it was at the commit.  The file
+// is downloaded from the raw url pinned to the full commit sha.
+func (gfs *FS) openRevision(name, sha string) (fs.File, error) {
+	tree, rel, err := gfs.findTree(name)
+	if err != nil {
+		return nil, err
+	}
+
+	oid, err := resolveCommit(context.Background(), gfs, tree.org, tree.repo, sha)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := strings.Join([]string{gfs.rawUrl, tree.org, tree.repo, oid, rel}, "/")
+	f := newFile(tree, path.Base(rel)+"@"+sha, withUrl(raw), withUnknownSize())
+
+	return f.newFileHandle()
+}
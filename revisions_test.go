@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevisions(t *testing.T) {
+	tests := []struct {
+		description string
+		path        string
+		payload     []string
+		expect      []Revision
+		bodies      []string
+		expectErr   bool
+		errIs       error
+	}{
+		{
+			description: "list the revisions of a file",
+			path:        "org/repo/git/main/config/app.yml",
+			payload:     []string{singleRepoWithHeadReponse, revisionsResponse001, revisionsResponse002},
+			expect: []Revision{
+				{
+					Sha:     "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+					Message: "Turn it on",
+					Author:  "monalisa",
+					Date:    time.Date(2023, 5, 2, 10, 0, 0, 0, time.UTC),
+				}, {
+					Sha:     "cccccccccccccccccccccccccccccccccccccccc",
+					Message: "Add the config",
+					Author:  "Mona Lisa",
+					Date:    time.Date(2023, 4, 1, 10, 0, 0, 0, time.UTC),
+				},
+			},
+			bodies: []string{
+				`"exp":"1111111111111111111111111111111111111111"`,
+				`"path":"config/app.yml"`,
+			},
+		}, {
+			description: "not a file in a tree",
+			path:        "org/repo/commits",
+			payload:     []string{singleRepoWithHeadReponse},
+			expectErr:   true,
+			errIs:       fs.ErrNotExist,
+		}, {
+			description: "a file pinned to a commit, but not in a tree",
+			path:        "org/repo/history/main/cccccccccccccccccccccccccccccccccccccccc/commit.json",
+			payload:     []string{singleRepoWithHeadReponse},
+			expectErr:   true,
+			errIs:       fs.ErrNotExist,
+		}, {
+			description: "an invalid path",
+			path:        "/org/repo",
+			expectErr:   true,
+			errIs:       fs.ErrInvalid,
+		}, {
+			description: "list the revisions, but there was a json error",
+			path:        "org/repo/git/main/config/app.yml",
+			payload:     []string{singleRepoWithHeadReponse, invalidJsonResponse},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gfs, _, bodies := newTestFS(t, fsTest{
+				opts:    []Option{WithRepo("org", "repo")},
+				payload: tc.payload,
+			})
+
+			got, err := gfs.Revisions(tc.path)
+			if tc.expectErr {
+				assert.Error(err)
+				if tc.errIs != nil {
+					assert.ErrorIs(err, tc.errIs)
+				}
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tc.expect, got)
+			for _, body := range tc.bodies {
+				assert.Contains(strings.Join(*bodies, "\n"), body)
+			}
+		})
+	}
+}
+
+func TestOpenRevision(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "read a file at a revision",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoWithHeadReponse, baseDirectoryResponse, commitResponse, readmeResponse},
+			contents: map[string]string{
+				"org/repo/git/main/config.yml@4b825dc": readmeResponse,
+			},
+			requests: []string{
+				"POST /",
+				"POST /",
+				"POST /",
+				"GET /org/repo/4b825dc642cb6eb9a060e54bf8d69288fbee4904/config.yml",
+			},
+		}, {
+			description: "a revision that doesn't exist",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoWithHeadReponse, baseDirectoryResponse, commitNotFoundResponse},
+			unexpected:  []string{"org/repo/git/main/config.yml@4b825dc"},
+		}, {
+			description: "a name that isn't a revision",
+			opts:        []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+			payload:     []string{singleRepoWithHeadReponse, baseDirectoryResponse},
+			unexpected:  []string{"org/repo/git/main/config.yml@main"},
+			requests: []string{
+				"POST /",
+				"POST /",
+			},
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+var revisionsResponse001 = `{
+  "data": {
+    "repository": {
+      "object": {
+        "history": {
+          "pageInfo": {
+            "hasNextPage": true,
+            "endCursor": "cmV2aXNpb246MQ=="
+          },
+          "edges": [
+            {
+              "node": {
+                "oid": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+                "message": "Turn it on",
+                "committedDate": "2023-05-02T10:00:00Z",
+                "author": {
+                  "name": "Mona Lisa",
+                  "user": {
+                    "login": "monalisa"
+                  }
+                }
+              }
+            }
+          ]
+        }
+      }
+    }
+  }
+}`
+
+var revisionsResponse002 = `{
+  "data": {
+    "repository": {
+      "object": {
+        "history": {
+          "pageInfo": {
+            "hasNextPage": false,
+            "endCursor": "cmV2aXNpb246Mg=="
+          },
+          "edges": [
+            {
+              "node": {
+                "oid": "cccccccccccccccccccccccccccccccccccccccc",
+                "message": "Add the config",
+                "committedDate": "2023-04-01T10:00:00Z",
+                "author": {
+                  "name": "Mona Lisa",
+                  "user": null
+                }
+              }
+            }
+          ]
+        }
+      }
+    }
+  }
+}`
@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a semantic version like v1.2.3-rc.1+build.5.  The build metadata
+// doesn't change the order of versions so it isn't kept.
+type semver struct {
+	major int
+	minor int
+	patch int
+	pre   []string
+}
+
+// parseSemver parses a version with an optional leading 'v'.  If partial is
+// true the minor and patch numbers may be left off, like "v1" or "1.2", and
+// are treated as 0.
+func parseSemver(s string, partial bool) (semver, bool) {
+	if strings.HasPrefix(s, "v") || strings.HasPrefix(s, "V") {
+		s = s[1:]
+	}
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, hasPre := strings.Cut(s, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 || (len(parts) < 3 && !partial) {
+		return semver{}, false
+	}
+
+	var nums [3]int
+	for i, part := range parts {
+		n, ok := parseSemverNumber(part)
+		if !ok {
+			return semver{}, false
+		}
+		nums[i] = n
+	}
+
+	v := semver{
+		major: nums[0],
+		minor: nums[1],
+		patch: nums[2],
+	}
+
+	if hasPre {
+		v.pre = strings.Split(pre, ".")
+		for _, id := range v.pre {
+			if !validSemverIdent(id) {
+				return semver{}, false
+			}
+		}
+	}
+
+	return v, true
+}
+
+// parseSemverNumber parses a number that has no sign or leading zeros.
+func parseSemverNumber(s string) (int, bool) {
+	if len(s) == 0 || (len(s) > 1 && s[0] == '0') {
+		return 0, false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// validSemverIdent checks a prerelease identifier is made of letters, digits
+// and hyphens, and that numeric identifiers have no leading zeros.
+func validSemverIdent(id string) bool {
+	if len(id) == 0 {
+		return false
+	}
+	numeric := true
+	for _, c := range id {
+		switch {
+		case c >= '0' && c <= '9':
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '-':
+			numeric = false
+		default:
+			return false
+		}
+	}
+	return !numeric || len(id) == 1 || id[0] != '0'
+}
+
+// compare returns -1, 0 or 1 if the version is lower than, the same as or
+// higher than the other version.  A prerelease is lower than the release.
+func (v semver) compare(o semver) int {
+	if c := compareInts(v.major, o.major); c != 0 {
+		return c
+	}
+	if c := compareInts(v.minor, o.minor); c != 0 {
+		return c
+	}
+	if c := compareInts(v.patch, o.patch); c != 0 {
+		return c
+	}
+
+	switch {
+	case len(v.pre) == 0 && len(o.pre) == 0:
+		return 0
+	case len(v.pre) == 0:
+		return 1
+	case len(o.pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.pre) && i < len(o.pre); i++ {
+		if c := compareIdents(v.pre[i], o.pre[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInts(len(v.pre), len(o.pre))
+}
+
+// compareIdents compares prerelease identifiers.  Numeric identifiers are
+// compared as numbers and are lower than the others, which are compared as
+// text.
+func compareIdents(a, b string) int {
+	an, aNum := parseSemverNumber(a)
+	bn, bNum := parseSemverNumber(b)
+	switch {
+	case aNum && bNum:
+		return compareInts(an, bn)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareInts returns -1, 0 or 1 if a is lower than, the same as or higher
+// than b.
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparator is one operator and version of a version constraint.
+type comparator struct {
+	op string
+	v  semver
+}
+
+// versionConstraint is a list of comparators that a version must all match.
+type versionConstraint []comparator
+
+// comparatorOps are the operators of comparators, with the longer ones first
+// so they match before their prefixes.
+var comparatorOps = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+// parseConstraint parses a constraint like ">=1.2, <2".  The comparators are
+// separated by commas and a version without an operator must match exactly.
+// An empty constraint allows every version.
+func parseConstraint(s string) (versionConstraint, error) {
+	var c versionConstraint
+	if len(strings.TrimSpace(s)) == 0 {
+		return c, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+
+		op := "="
+		for _, candidate := range comparatorOps {
+			if strings.HasPrefix(part, candidate) {
+				op = candidate
+				part = strings.TrimSpace(strings.TrimPrefix(part, candidate))
+				break
+			}
+		}
+
+		v, ok := parseSemver(part, true)
+		if !ok {
+			return nil, fmt.Errorf("invalid version constraint '%s'", s)
+		}
+		c = append(c, comparator{op: op, v: v})
+	}
+
+	return c, nil
+}
+
+// allows checks if the version matches all of the comparators.  A prerelease
+// is only allowed if a comparator has a prerelease of the same version, so
+// "<2" doesn't allow 2.0.0-rc.1 but ">=2.0.0-rc.1" does.
+func (c versionConstraint) allows(v semver) bool {
+	if len(c) > 0 && len(v.pre) > 0 && !c.hasPrerelease(v) {
+		return false
+	}
+
+	for _, cmp := range c {
+		n := v.compare(cmp.v)
+
+		var ok bool
+		switch cmp.op {
+		case "=", "==":
+			ok = n == 0
+		case "!=":
+			ok = n != 0
+		case ">":
+			ok = n > 0
+		case ">=":
+			ok = n >= 0
+		case "<":
+			ok = n < 0
+		case "<=":
+			ok = n <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasPrerelease checks if a comparator has a prerelease of the same major,
+// minor and patch version as the version.
+func (c versionConstraint) hasPrerelease(v semver) bool {
+	for _, cmp := range c {
+		if len(cmp.v.pre) > 0 && cmp.v.major == v.major && cmp.v.minor == v.minor && cmp.v.patch == v.patch {
+			return true
+		}
+	}
+	return false
+}
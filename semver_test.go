@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		in      string
+		partial bool
+		expect  semver
+		invalid bool
+	}{
+		{in: "1.2.3", expect: semver{major: 1, minor: 2, patch: 3}},
+		{in: "v1.2.3", expect: semver{major: 1, minor: 2, patch: 3}},
+		{in: "v0.6.8-rc.1", expect: semver{minor: 6, patch: 8, pre: []string{"rc", "1"}}},
+		{in: "v1.0.0+build.5", expect: semver{major: 1}},
+		{in: "v1.0.0-alpha-2+build", expect: semver{major: 1, pre: []string{"alpha-2"}}},
+		{in: "v1", partial: true, expect: semver{major: 1}},
+		{in: "1.2", partial: true, expect: semver{major: 1, minor: 2}},
+		{in: "v1", invalid: true},
+		{in: "1.2", invalid: true},
+		{in: "1.2.3.4", invalid: true},
+		{in: "01.2.3", invalid: true},
+		{in: "1.2.x", invalid: true},
+		{in: "1.2.-3", invalid: true},
+		{in: "1.2.3-", invalid: true},
+		{in: "1.2.3-rc..1", invalid: true},
+		{in: "1.2.3-01", invalid: true},
+		{in: "1.2.3-rc_1", invalid: true},
+		{in: "vv1.2.3", invalid: true},
+		{in: "vV1.2.3", invalid: true},
+		{in: "V1.2.3", expect: semver{major: 1, minor: 2, patch: 3}},
+		{in: "nightly", invalid: true},
+		{in: "", invalid: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			got, ok := parseSemver(tc.in, tc.partial)
+			if tc.invalid {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, tc.expect, got)
+		})
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	// In order from lowest to highest, from the semver spec.
+	ordered := []string{
+		"0.9.9",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.2.0",
+		"1.10.0",
+		"2.0.0",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			a, ok := parseSemver(ordered[i], false)
+			require.True(t, ok)
+			b, ok := parseSemver(ordered[j], false)
+			require.True(t, ok)
+			assert.Equal(t, compareInts(i, j), a.compare(b), "%s vs %s", ordered[i], ordered[j])
+		}
+	}
+}
+
+func TestVersionConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		allows     []string
+		rejects    []string
+		invalid    bool
+	}{
+		{
+			constraint: ">=1.2, <2",
+			allows:     []string{"v1.2.0", "v1.10.3"},
+			rejects:    []string{"v1.1.9", "v2.0.0", "v1.2.0-rc.1", "v2.0.0-rc.1", "v1.5.0-rc.1"},
+		}, {
+			constraint: "<2",
+			allows:     []string{"v1.9.9", "v0.1.0"},
+			rejects:    []string{"v2.0.0-rc.1", "v2.0.0-alpha", "v1.9.9-rc.1"},
+		}, {
+			constraint: ">=2.0.0-rc.1",
+			allows:     []string{"v2.0.0-rc.1", "v2.0.0-rc.2", "v2.0.0", "v2.1.0"},
+			rejects:    []string{"v2.0.0-beta.1", "v2.1.0-rc.1", "v1.9.9"},
+		}, {
+			constraint: "1.2.3",
+			allows:     []string{"v1.2.3"},
+			rejects:    []string{"v1.2.4"},
+		}, {
+			constraint: "== v1.2.3",
+			allows:     []string{"v1.2.3"},
+			rejects:    []string{"v1.2.4"},
+		}, {
+			constraint: "!=1.2.3,>1",
+			allows:     []string{"v1.2.4"},
+			rejects:    []string{"v1.2.3", "v1.0.0"},
+		}, {
+			constraint: "<=1.2",
+			allows:     []string{"v1.2.0", "v0.1.0"},
+			rejects:    []string{"v1.2.1"},
+		}, {
+			constraint: ">=1.2,",
+			invalid:    true,
+		}, {
+			constraint: "~1.2",
+			invalid:    true,
+		}, {
+			constraint: "",
+			allows:     []string{"v0.1.0", "v1.2.3", "v2.0.0-rc.1"},
+		}, {
+			constraint: " ",
+			allows:     []string{"v1.2.3"},
+		}, {
+			constraint: "vv1.2.3",
+			invalid:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.constraint, func(t *testing.T) {
+			c, err := parseConstraint(tc.constraint)
+			if tc.invalid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, s := range tc.allows {
+				v, ok := parseSemver(s, false)
+				require.True(t, ok)
+				assert.True(t, c.allows(v), s)
+			}
+			for _, s := range tc.rejects {
+				v, ok := parseSemver(s, false)
+				require.True(t, ok)
+				assert.False(t, c.allows(v), s)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+)
+
+// wikiSuffix is added to the name of a repository to get the name of the
+// repository holding its wiki.
+const wikiSuffix = ".wiki"
+
+// getWikiDir fetches the wiki with the git smart http protocol since the wiki
+// repository isn't available through the GraphQL API.  Only the pages at the
+// head of the default branch are fetched and they are kept in memory, which
+// suits the size of most wikis.  The wiki branch is recorded by Lock() and
+// pinned by WithLock() like any other branch, but only the head commit is
+// fetched so the wiki isn't read as of the time from WithAsOf().
+//
+// A wiki that is enabled but has no pages yet, or that the token can't read,
+// is an empty directory.
+func getWikiDir(gfs *FS, d *dir) error {
+	repoUrl := strings.Join([]string{gfs.gitUrl, d.org, d.repo + ".git"}, "/")
+
+	branch, head, err := gitHead(context.Background(), gfs, repoUrl)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if len(branch) == 0 || len(head) == 0 {
+		return nil
+	}
+
+	key := lockKey(d.org, d.repo, branch)
+	oid, found := gfs.pinned(key)
+	if !found {
+		oid = head
+	}
+
+	objects, err := gitFetch(context.Background(), gfs, repoUrl, oid)
+	if err != nil {
+		return err
+	}
+
+	tree, modTime, err := gitCommitTree(objects, oid)
+	if err != nil {
+		return err
+	}
+
+	if err = gitTreeToDir(objects, tree, d, modTime); err != nil {
+		return err
+	}
+
+	gfs.lock[key] = oid
+	d.branch = branch
+	d.commit = oid
+	d.modTime = modTime
+
+	return nil
+}
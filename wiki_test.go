@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWiki(t *testing.T) {
+	tests := []fsTest{
+		{
+			description: "fetch the wiki",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithWikiReponse, wikiRefsResponse, wikiPackResponse},
+			expect:      []string{"org/repo/wiki", "org/repo/wiki/Home.md", "org/repo/wiki/tools/run.sh"},
+			contents: map[string]string{
+				"org/repo/wiki/Home.md":      wikiHome,
+				"org/repo/wiki/tools/run.sh": wikiScript,
+			},
+			requests: []string{
+				"POST /",
+				"GET /org/repo.wiki.git/info/refs?service=git-upload-pack",
+				"POST /org/repo.wiki.git/git-upload-pack",
+			},
+			bodies: []string{"want " + wikiCommitSha + " ofs-delta\n", "deepen 1\n"},
+		}, {
+			description: "fetch the wiki at the locked commit",
+			opts: []Option{
+				WithRepo("org", "repo"),
+				WithLock(Manifest{Branches: []LockedBranch{{Org: "org", Repo: "repo.wiki", Branch: "master", Commit: wikiCommitSha}}}),
+			},
+			payload: []string{singleRepoWithWikiReponse, testRefs("master", "1111111111111111111111111111111111111111"), wikiPackResponse},
+			expect:  []string{"org/repo/wiki/Home.md"},
+			bodies:  []string{"want " + wikiCommitSha + " ofs-delta\n"},
+		}, {
+			description: "a wiki without any pages is empty",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithWikiReponse, testRefs("", "")},
+			expect:      []string{"org/repo/wiki"},
+			unexpected:  []string{"org/repo/wiki/Home.md"},
+		}, {
+			description: "a wiki that can't be found is empty",
+			opts:        []Option{WithRepo("org", "repo")},
+			statusCode:  []int{0, 404},
+			payload:     []string{singleRepoWithWikiReponse, "Not Found"},
+			expect:      []string{"org/repo/wiki"},
+			unexpected:  []string{"org/repo/wiki/Home.md"},
+		}, {
+			description: "a repo without the wiki enabled has no wiki directory",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoReponse},
+			unexpected:  []string{"org/repo/wiki"},
+		}, {
+			description: "fetch the wiki, but the server failed",
+			opts:        []Option{WithRepo("org", "repo")},
+			statusCode:  []int{0, 500},
+			payload:     []string{singleRepoWithWikiReponse},
+			expect:      []string{"org/repo/wiki"},
+			expectErr:   true,
+		}, {
+			description: "fetch the wiki, but it isn't a git server",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithWikiReponse, "<html></html>"},
+			expect:      []string{"org/repo/wiki"},
+			expectErr:   true,
+		}, {
+			description: "fetch the wiki, but the fetch failed",
+			opts:        []Option{WithRepo("org", "repo")},
+			payload:     []string{singleRepoWithWikiReponse, wikiRefsResponse, "0019ERR upload-pack: no\n"},
+			expect:      []string{"org/repo/wiki"},
+			expectErr:   true,
+		},
+	}
+
+	runFSTests(t, tests)
+}
+
+func TestWikiLock(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, _, _ := newTestFS(t, fsTest{
+		opts:    []Option{WithRepo("org", "repo")},
+		payload: []string{singleRepoWithWikiReponse, wikiRefsResponse, wikiPackResponse},
+	})
+
+	info, err := fs.Stat(gfs, "org/repo/wiki/Home.md")
+	require.NoError(err)
+	assert.Equal(wikiCommitTime, info.ModTime().Unix())
+
+	m, err := gfs.Lock()
+	require.NoError(err)
+	assert.Contains(m.Branches, LockedBranch{Org: "org", Repo: "repo.wiki", Branch: "master", Commit: wikiCommitSha})
+}
+
+var singleRepoWithWikiReponse = `{
+  "data": {
+    "repository": {
+      "diskUsage": 18,
+      "isArchived": false,
+      "isDisabled": false,
+      "hasWikiEnabled": true,
+      "nameWithOwner": "org/repo",
+      "defaultBranchRef": {
+        "name": "main"
+      },
+      "releases": {
+        "totalCount": 0
+      }
+    }
+  }
+}`
+
+var (
+	wikiHome       = "# Welcome\n"
+	wikiScript     = "#!/bin/sh\necho hi\n"
+	wikiCommitTime = int64(1600000000)
+
+	wikiHomeSha   = gitObjectSha(gitObject{kind: gitObjBlob, data: []byte(wikiHome)})
+	wikiScriptSha = gitObjectSha(gitObject{kind: gitObjBlob, data: []byte(wikiScript)})
+	wikiToolsTree = testTree("100755 run.sh", wikiScriptSha)
+	wikiToolsSha  = gitObjectSha(gitObject{kind: gitObjTree, data: wikiToolsTree})
+	wikiTree      = testTree("100644 Home.md", wikiHomeSha, "40000 tools", wikiToolsSha)
+	wikiTreeSha   = gitObjectSha(gitObject{kind: gitObjTree, data: wikiTree})
+	wikiCommit    = []byte(fmt.Sprintf("tree %s\nauthor a <a@b> %d +0000\ncommitter a <a@b> %d +0000\n\nInitial\n",
+		wikiTreeSha, wikiCommitTime, wikiCommitTime))
+	wikiCommitSha = gitObjectSha(gitObject{kind: gitObjCommit, data: wikiCommit})
+
+	wikiRefsResponse = testRefs("master", wikiCommitSha)
+	wikiPackResponse = testUploadPack(wikiCommitSha, testPack(
+		testPackObject(gitObjCommit, wikiCommit),
+		testPackObject(gitObjTree, wikiTree),
+		testPackObject(gitObjTree, wikiToolsTree),
+		testPackObject(gitObjBlob, []byte(wikiHome)),
+		testPackObject(gitObjBlob, []byte(wikiScript)),
+	))
+)
@@ -0,0 +1,467 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// changeSet is the changes staged for a branch that haven't been committed.
+// The paths are relative to the root of the branch.
+type changeSet struct {
+	tree      *dir
+	additions map[string][]byte
+	deletions map[string]bool
+}
+
+// commitInput is the github CreateCommitOnBranchInput used by the
+// createCommitOnBranch mutation.
+type commitInput struct {
+	Branch          commitBranch      `json:"branch"`
+	Message         commitMessage     `json:"message"`
+	ExpectedHeadOid string            `json:"expectedHeadOid"`
+	FileChanges     commitFileChanges `json:"fileChanges"`
+}
+
+// GetGraphQLType returns the name of the github type.
+func (commitInput) GetGraphQLType() string {
+	return "CreateCommitOnBranchInput"
+}
+
+// commitBranch is the branch a commit is made on.
+type commitBranch struct {
+	RepositoryNameWithOwner string `json:"repositoryNameWithOwner"`
+	BranchName              string `json:"branchName"`
+}
+
+// commitMessage is the message of a commit split into the first line and the
+// rest.
+type commitMessage struct {
+	Headline string `json:"headline"`
+	Body     string `json:"body,omitempty"`
+}
+
+// commitFileChanges are the files added, changed and deleted by a commit.
+type commitFileChanges struct {
+	Additions []commitAddition `json:"additions,omitempty"`
+	Deletions []commitDeletion `json:"deletions,omitempty"`
+}
+
+// commitAddition is a file added or changed by a commit.
+type commitAddition struct {
+	Path     string `json:"path"`
+	Contents string `json:"contents"`
+}
+
+// commitDeletion is a file deleted by a commit.
+type commitDeletion struct {
+	Path string `json:"path"`
+}
+
+// WriteFile stages the data to be written to the named file when the branch
+// is committed with Commit().  The path must be in a branch like
+// org/repo/git/main/config.yml.  Files that don't exist are created.  Reads
+// return the committed contents until the change is committed.
+//
+// Files written to a release like org/repo/releases/v1.0.0/app.tar.gz are
+// uploaded as release assets right away instead, and writing description.md
+// replaces the description of the release.
+func (gfs *FS) WriteFile(name string, data []byte) error {
+	if _, _, _, _, ok := releasePath(name); ok {
+		if err := gfs.writeRelease(context.Background(), name, bytes.NewReader(data), int64(len(data))); err != nil {
+			return fmt.Errorf("write %s %w", name, err)
+		}
+		return nil
+	}
+
+	if err := gfs.staged.write(gfs, name, data); err != nil {
+		return fmt.Errorf("write %s %w", name, err)
+	}
+	return nil
+}
+
+// Remove stages the removal of the named file when the branch is committed
+// with Commit().  The path must be a file in a branch like
+// org/repo/git/main/config.yml, or a file staged by WriteFile().
+func (gfs *FS) Remove(name string) error {
+	if err := gfs.staged.remove(gfs, name); err != nil {
+		return fmt.Errorf("remove %s %w", name, err)
+	}
+	return nil
+}
+
+// Rename stages moving the file from the old name to the new name when the
+// branch is committed with Commit().  Both names must be in the same branch.
+// A file at the new name is replaced.  A file staged to be removed can't be
+// renamed.
+func (gfs *FS) Rename(oldname, newname string) error {
+	if err := gfs.staged.rename(gfs, oldname, newname); err != nil {
+		return fmt.Errorf("rename %s %w", oldname, err)
+	}
+	return nil
+}
+
+// Commit commits the changes staged for the branch at the path, like
+// org/repo/git/main, as one commit with the message.  The first line of the
+// message is the headline and the rest is the body.  The commit is only made
+// if the branch is still at the commit the filesystem read it at, so changes
+// made by others are never overwritten.  On success the branch is read at the
+// new commit and the sha of the commit is returned.
+func (gfs *FS) Commit(ctx context.Context, name, message string) (string, error) {
+	if len(strings.TrimSpace(message)) == 0 {
+		return "", fmt.Errorf("commit %s an empty message %w", name, fs.ErrInvalid)
+	}
+
+	if err := gfs.connect(); err != nil {
+		return "", fmt.Errorf("commit %s error connecting: %w", name, err)
+	}
+
+	key := path.Clean(name)
+	tree, additions, deletions, found := gfs.staged.get(key)
+	if !found {
+		return "", fmt.Errorf("commit %s nothing is staged %w", name, fs.ErrInvalid)
+	}
+
+	head, err := treeHead(ctx, gfs, tree)
+	if err != nil {
+		return "", fmt.Errorf("commit %s %w", name, err)
+	}
+
+	oid, when, err := commitOnBranch(ctx, gfs, tree.org, tree.repo, tree.branch, head, message, additions, deletions)
+	if err != nil {
+		return "", fmt.Errorf("commit %s %w", name, err)
+	}
+	gfs.staged.drop(key)
+
+	// Read the branch at the new commit from now on.
+	gfs.lock[lockKey(tree.org, tree.repo, tree.branch)] = oid
+	tree.commit = oid
+	tree.modTime = when
+	tree.children = make(map[string]any)
+	tree.fetchFn = gfs.gitDirFn(tree.size)
+
+	return oid, nil
+}
+
+// treeHead returns the commit the branch is read at, or the head of the
+// branch if it isn't pinned.
+func treeHead(ctx context.Context, gfs *FS, tree *dir) (string, error) {
+	if len(tree.commit) > 0 {
+		return tree.commit, nil
+	}
+	return resolveCommit(ctx, gfs, tree.org, tree.repo, tree.branch)
+}
+
+// commitOnBranch commits the changes to the branch as long as the branch is
+// still at the head commit, and returns the sha and time of the new commit.
+// The first line of the message is the headline and the rest is the body.
+func commitOnBranch(ctx context.Context, gfs *FS, org, repo, branch, head, message string,
+	additions map[string][]byte, deletions []string) (string, time.Time, error) {
+	headline, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+
+	input := commitInput{
+		Branch: commitBranch{
+			RepositoryNameWithOwner: org + "/" + repo,
+			BranchName:              branch,
+		},
+		Message: commitMessage{
+			Headline: strings.TrimSpace(headline),
+			Body:     strings.TrimSpace(body),
+		},
+		ExpectedHeadOid: head,
+	}
+	for _, p := range sortedKeys(additions) {
+		input.FileChanges.Additions = append(input.FileChanges.Additions, commitAddition{
+			Path:     p,
+			Contents: base64.StdEncoding.EncodeToString(additions[p]),
+		})
+	}
+	for _, p := range deletions {
+		input.FileChanges.Deletions = append(input.FileChanges.Deletions, commitDeletion{
+			Path: p,
+		})
+	}
+
+	/*
+		mutation {
+		  createCommitOnBranch(input: {
+		    branch: {repositoryNameWithOwner: "org/repo", branchName: "main"},
+		    message: {headline: "Update the config"},
+		    expectedHeadOid: "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+		    fileChanges: {
+		      additions: [{path: "config.yml", contents: "b246IHRydWUK"}],
+		      deletions: [{path: "old.yml"}]
+		    }
+		  }) {
+		    commit {
+		      oid
+		      committedDate
+		    }
+		  }
+		}
+	*/
+	var m struct {
+		CreateCommitOnBranch struct {
+			Commit struct {
+				Oid           string
+				CommittedDate time.Time
+			}
+		} `graphql:"createCommitOnBranch(input: $input)"`
+	}
+
+	vars := map[string]any{
+		"input": input,
+	}
+
+	if err := gfs.gqlClient.Mutate(ctx, &m, vars); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return m.CreateCommitOnBranch.Commit.Oid, m.CreateCommitOnBranch.Commit.CommittedDate, nil
+}
+
+// staging holds the changes staged for each branch by the path of the branch.
+type staging struct {
+	m    sync.Mutex
+	sets map[string]*changeSet
+}
+
+// changes finds the changes staged for the branch the path is in, and returns
+// them with the path relative to the branch.
+func (s *staging) changes(gfs *FS, name string) (*changeSet, string, error) {
+	if !fs.ValidPath(name) {
+		return nil, "", fs.ErrInvalid
+	}
+
+	if err := gfs.connect(); err != nil {
+		return nil, "", fmt.Errorf("error connecting: %w", err)
+	}
+
+	tree, rel, err := gfs.findTree(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := tree.fullPath()
+	parts := strings.Split(key, "/")
+	if len(tree.branch) == 0 || len(parts) < 4 || parts[2] != dirNameGit || strings.Contains(tree.name, "@") {
+		return nil, "", fmt.Errorf("%s is not in a branch %w", name, fs.ErrPermission)
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.sets == nil {
+		s.sets = make(map[string]*changeSet)
+	}
+	cs, found := s.sets[key]
+	if !found {
+		cs = &changeSet{
+			tree:      tree,
+			additions: make(map[string][]byte),
+			deletions: make(map[string]bool),
+		}
+		s.sets[key] = cs
+	}
+
+	return cs, rel, nil
+}
+
+// write stages the data to be written to the file.
+func (s *staging) write(gfs *FS, name string, data []byte) error {
+	cs, rel, err := s.changes(gfs, name)
+	if err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	cs.additions[rel] = append([]byte{}, data...)
+	delete(cs.deletions, rel)
+
+	return nil
+}
+
+// remove stages the removal of a file that is either in the branch or staged
+// to be written.
+func (s *staging) remove(gfs *FS, name string) error {
+	cs, rel, err := s.changes(gfs, name)
+	if err != nil {
+		return err
+	}
+
+	_, f, err := gfs.root.find(name)
+	exists := err == nil && f != nil
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	_, staged := cs.additions[rel]
+	if !exists && !staged {
+		return fmt.Errorf("%s is not a file %w", name, fs.ErrNotExist)
+	}
+
+	delete(cs.additions, rel)
+	if exists {
+		cs.deletions[rel] = true
+	}
+
+	return nil
+}
+
+// rename stages moving the file from the old name to the new name.
+func (s *staging) rename(gfs *FS, oldname, newname string) error {
+	oldCs, oldRel, err := s.changes(gfs, oldname)
+	if err != nil {
+		return err
+	}
+	newCs, newRel, err := s.changes(gfs, newname)
+	if err != nil {
+		return err
+	}
+	if oldCs != newCs {
+		return fmt.Errorf("rename to %s across branches %w", newname, fs.ErrInvalid)
+	}
+
+	s.m.Lock()
+	data, found := oldCs.additions[oldRel]
+	removed := oldCs.deletions[oldRel]
+	s.m.Unlock()
+	if removed {
+		return fmt.Errorf("%s is removed %w", oldname, fs.ErrNotExist)
+	}
+	if !found {
+		data, err = fs.ReadFile(gfs, oldname)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = s.remove(gfs, oldname); err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	newCs.additions[newRel] = data
+	delete(newCs.deletions, newRel)
+
+	return nil
+}
+
+// get returns the branch and a copy of the changes staged for the branch at
+// the path.  The deletions are in order.
+func (s *staging) get(key string) (*dir, map[string][]byte, []string, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	cs, found := s.sets[key]
+	if !found {
+		return nil, nil, nil, false
+	}
+
+	additions := make(map[string][]byte, len(cs.additions))
+	for p, data := range cs.additions {
+		additions[p] = data
+	}
+
+	return cs.tree, additions, sortedKeys(cs.deletions), true
+}
+
+// drop forgets the changes staged for the branch at the path.
+func (s *staging) drop(key string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	delete(s.sets, key)
+}
+
+// branches returns the paths of the branches with staged changes in order.
+func (s *staging) branches() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return sortedKeys(s.sets)
+}
+
+// file returns the staged contents of the file at the path if it is staged
+// to be written, or reports if it is staged to be removed.
+func (s *staging) file(name string) ([]byte, bool, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for key, cs := range s.sets {
+		if !strings.HasPrefix(name, key+"/") {
+			continue
+		}
+		rel := strings.TrimPrefix(name, key+"/")
+		if data, found := cs.additions[rel]; found {
+			return data, true, false
+		}
+		return nil, false, cs.deletions[rel]
+	}
+
+	return nil, false, false
+}
+
+// entries returns the files and directories staged to be written directly in
+// the directory at the path, and the names of the files staged to be removed.
+func (s *staging) entries(name string) (map[string]*fileInfo, map[string]bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	added := make(map[string]*fileInfo)
+	removed := make(map[string]bool)
+	for key, cs := range s.sets {
+		var prefix string
+		switch {
+		case name == key:
+		case strings.HasPrefix(name, key+"/"):
+			prefix = strings.TrimPrefix(name, key+"/") + "/"
+		default:
+			continue
+		}
+
+		for rel, data := range cs.additions {
+			if !strings.HasPrefix(rel, prefix) {
+				continue
+			}
+			child, _, isDir := strings.Cut(strings.TrimPrefix(rel, prefix), "/")
+			if isDir {
+				added[child] = &fileInfo{name: child, size: 4096, mode: fs.ModeDir | 0755}
+				continue
+			}
+			added[child] = &fileInfo{name: child, size: int64(len(data)), mode: fs.FileMode(0644)}
+		}
+		for rel := range cs.deletions {
+			rest := strings.TrimPrefix(rel, prefix)
+			if strings.HasPrefix(rel, prefix) && !strings.Contains(rest, "/") {
+				removed[rest] = true
+			}
+		}
+	}
+
+	return added, removed
+}
+
+// sortedKeys returns the keys of the map in order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
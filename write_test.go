@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2022 Weston Schmidt <weston_schmidt@alumni.purdue.edu>
+// SPDX-License-Identifier: Apache-2.0
+
+package githubfs
+
+import (
+	"context"
+	"encoding/base64"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommit(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	gfs, requests, bodies := newTestFS(t, fsTest{
+		opts: []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+		payload: []string{
+			singleRepoWithHeadReponse,
+			baseDirectoryResponse,
+			readmeResponse,
+			commitMutationResponse,
+			baseDirectoryResponse,
+		},
+	})
+
+	require.NoError(gfs.WriteFile("org/repo/git/main/config/app.yml", []byte("on: true\n")))
+	require.NoError(gfs.Remove("org/repo/git/main/executable"))
+	require.NoError(gfs.Rename("org/repo/git/main/README.md", "org/repo/git/main/docs/README.md"))
+
+	// Nothing is committed until asked.
+	assert.Equal([]string{
+		"POST /",
+		"POST /",
+		"GET /org/repo/1111111111111111111111111111111111111111/README.md",
+	}, *requests)
+
+	oid, err := gfs.Commit(context.Background(), "org/repo/git/main", "Update the config\n\nTurn it on.\n")
+	require.NoError(err)
+	assert.Equal("3333333333333333333333333333333333333333", oid)
+
+	mutation := (*bodies)[3]
+	assert.Contains(mutation, "createCommitOnBranch(input: $input)")
+	assert.Contains(mutation, `"branch":{"repositoryNameWithOwner":"org/repo","branchName":"main"}`)
+	assert.Contains(mutation, `"message":{"headline":"Update the config","body":"Turn it on."}`)
+	assert.Contains(mutation, `"expectedHeadOid":"1111111111111111111111111111111111111111"`)
+	assert.Contains(mutation, `"additions":[`+
+		`{"path":"config/app.yml","contents":"`+base64.StdEncoding.EncodeToString([]byte("on: true\n"))+`"},`+
+		`{"path":"docs/README.md","contents":"`+base64.StdEncoding.EncodeToString([]byte(readmeResponse))+`"}]`)
+	assert.Contains(mutation, `"deletions":[{"path":"README.md"},{"path":"executable"}]`)
+
+	// The branch is read at the new commit.
+	m, err := gfs.Lock()
+	require.NoError(err)
+	assert.Equal("3333333333333333333333333333333333333333", m.Branches[0].Commit)
+
+	_, err = fs.ReadDir(gfs, "org/repo/git/main")
+	require.NoError(err)
+	assert.Contains((*bodies)[4], `"exp":"3333333333333333333333333333333333333333:"`)
+
+	// The staged changes are gone.
+	_, err = gfs.Commit(context.Background(), "org/repo/git/main", "Again")
+	assert.ErrorIs(err, fs.ErrInvalid)
+}
+
+func TestCommitErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		payload     []string
+		fn          func(*FS) error
+		errIs       error
+	}{
+		{
+			description: "write an invalid path",
+			payload:     []string{singleRepoWithHeadReponse},
+			fn: func(gfs *FS) error {
+				return gfs.WriteFile("/org/repo/git/main/a.yml", nil)
+			},
+			errIs: fs.ErrInvalid,
+		}, {
+			description: "write a path that isn't in a tree",
+			payload:     []string{singleRepoWithHeadReponse},
+			fn: func(gfs *FS) error {
+				return gfs.WriteFile("org/repo/git", nil)
+			},
+			errIs: fs.ErrNotExist,
+		}, {
+			description: "write a file in a commit",
+			payload:     []string{singleRepoWithHeadReponse, commitResponse},
+			fn: func(gfs *FS) error {
+				return gfs.WriteFile("org/repo/commits/4b825dc/a.yml", nil)
+			},
+			errIs: fs.ErrPermission,
+		}, {
+			description: "remove a file that doesn't exist",
+			payload:     []string{singleRepoWithHeadReponse, baseDirectoryResponse},
+			fn: func(gfs *FS) error {
+				return gfs.Remove("org/repo/git/main/missing.yml")
+			},
+			errIs: fs.ErrNotExist,
+		}, {
+			description: "rename a file that doesn't exist",
+			payload:     []string{singleRepoWithHeadReponse, baseDirectoryResponse},
+			fn: func(gfs *FS) error {
+				return gfs.Rename("org/repo/git/main/missing.yml", "org/repo/git/main/a.yml")
+			},
+			errIs: fs.ErrNotExist,
+		}, {
+			description: "rename a file that is staged to be removed",
+			payload:     []string{singleRepoWithHeadReponse, baseDirectoryResponse},
+			fn: func(gfs *FS) error {
+				if err := gfs.Remove("org/repo/git/main/README.md"); err != nil {
+					return err
+				}
+				return gfs.Rename("org/repo/git/main/README.md", "org/repo/git/main/a.md")
+			},
+			errIs: fs.ErrNotExist,
+		}, {
+			description: "commit with nothing staged",
+			payload:     []string{singleRepoWithHeadReponse},
+			fn: func(gfs *FS) error {
+				_, err := gfs.Commit(context.Background(), "org/repo/git/main", "Nothing")
+				return err
+			},
+			errIs: fs.ErrInvalid,
+		}, {
+			description: "commit with an empty message",
+			payload:     []string{singleRepoWithHeadReponse},
+			fn: func(gfs *FS) error {
+				if err := gfs.WriteFile("org/repo/git/main/a.yml", nil); err != nil {
+					return err
+				}
+				_, err := gfs.Commit(context.Background(), "org/repo/git/main", " \n")
+				return err
+			},
+			errIs: fs.ErrInvalid,
+		}, {
+			description: "the branch moved before the commit",
+			payload:     []string{singleRepoWithHeadReponse, commitMutationErrorResponse},
+			fn: func(gfs *FS) error {
+				if err := gfs.WriteFile("org/repo/git/main/a.yml", nil); err != nil {
+					return err
+				}
+				_, err := gfs.Commit(context.Background(), "org/repo/git/main", "Add a")
+				return err
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gfs, _, _ := newTestFS(t, fsTest{
+				opts:    []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+				payload: tc.payload,
+			})
+
+			err := tc.fn(gfs)
+			assert.Error(err)
+			if tc.errIs != nil {
+				assert.ErrorIs(err, tc.errIs)
+			}
+		})
+	}
+}
+
+func TestRemoveStaged(t *testing.T) {
+	assert := assert.New(t)
+
+	gfs, _, _ := newTestFS(t, fsTest{
+		opts:    []Option{WithRepo("org", "repo"), WithThresholdInKB(0)},
+		payload: []string{singleRepoWithHeadReponse, baseDirectoryResponse},
+	})
+
+	assert.NoError(gfs.WriteFile("org/repo/git/main/a.yml", []byte("a")))
+	assert.NoError(gfs.Remove("org/repo/git/main/a.yml"))
+
+	cs := gfs.staged.sets["org/repo/git/main"]
+	assert.Empty(cs.additions)
+	assert.Empty(cs.deletions)
+}
+
+var commitMutationResponse = `{
+  "data": {
+    "createCommitOnBranch": {
+      "commit": {
+        "oid": "3333333333333333333333333333333333333333",
+        "committedDate": "2023-06-01T10:00:00Z"
+      }
+    }
+  }
+}`
+
+var commitMutationErrorResponse = `{
+  "data": {
+    "createCommitOnBranch": null
+  },
+  "errors": [
+    {
+      "type": "STALE_DATA",
+      "message": "Expected branch to point to \"1111111111111111111111111111111111111111\" but it did not."
+    }
+  ]
+}`